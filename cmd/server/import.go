@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/importer"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+)
+
+// runImport implements `server import`: it imports a Todoist, Trello or
+// Microsoft To Do export for one user and prints the report as JSON.
+func runImport(db *gorm.DB, cfg *config.Config, blobs storage.BlobStore, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	source := fs.String("source", "", "todoist, trello or mstodo")
+	email := fs.String("user", "", "email of the user to import for")
+	dryRun := fs.Bool("dry-run", false, "validate only, save nothing")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: server import -source todoist|trello|mstodo -user EMAIL [-dry-run] FILE")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *email == "" {
+		fs.Usage()
+		return fmt.Errorf("a user and exactly one file are required")
+	}
+	src, err := importer.ParseSource(*source)
+	if err != nil {
+		return err
+	}
+	file := fs.Arg(0)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	users := repository.NewUserRepository(db)
+	u, err := users.FindByEmail(*email)
+	if err != nil {
+		return fmt.Errorf("user %s: %w", *email, err)
+	}
+	report, err := newImportJobService(db, cfg, blobs).Run(src, filepath.Base(file), data, *dryRun, service.Actor{ID: u.ID, Role: u.Role})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d created, %d updated, %d failed, %d skipped\n",
+		report.Created, report.Updated, report.Failed, len(report.Skipped))
+	return nil
+}
+
+// newImportJobService wires the import service outside the HTTP app, for
+// the CLI and the background job worker.
+func newImportJobService(db *gorm.DB, cfg *config.Config, blobs storage.BlobStore) service.ImportJobService {
+	users := repository.NewUserRepository(db)
+	tx := repository.NewTxManager(db)
+	access := service.NewAccessControl(repository.NewShareRepository(db), repository.NewProjectRepository(db))
+	todos := service.NewTodoService(
+		repository.NewTodoRepository(db),
+		repository.NewTodoItemRepository(db),
+		repository.NewReminderRepository(db),
+		repository.NewActivityRepository(db),
+		users,
+		repository.NewAttachmentRepository(db),
+		repository.NewOutboxRepository(db),
+		blobs,
+		access,
+		tx,
+	)
+	return service.NewImportJobService(repository.NewImportJobRepository(db), users, todos, tx, cfg.JobStaleAfter)
+}
@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // user timezones must resolve in minimal images
 
 	"github.com/joho/godotenv"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/database"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/events"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/jobs"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/notify"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/realtime"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/routes"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/scheduler"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
 )
 
 func main() {
@@ -20,10 +35,111 @@ func main() {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
-	app := routes.NewFiberApp(cfg, db)
+	blobs, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to set up storage: %v", err)
+	}
+
+	if len(os.Args) > 1 {
+		switch cmd := os.Args[1]; cmd {
+		case "serve":
+		case "migrate":
+			if err := runMigrate(db, cfg, os.Args[2:]); err != nil {
+				log.Fatalf("migrate: %v", err)
+			}
+			return
+		case "import":
+			if err := runImport(db, cfg, blobs, os.Args[2:]); err != nil {
+				log.Fatalf("import: %v", err)
+			}
+			return
+		default:
+			log.Fatalf("unknown command %q (want serve, migrate or import)", cmd)
+		}
+	}
+
+	if err := database.Prepare(db, cfg); err != nil {
+		log.Fatalf("failed to prepare database: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dispatcher := notify.NewDispatcher()
+	if cfg.SMTPHost != "" {
+		dispatcher.Register(string(models.ReminderEmail), &notify.EmailChannel{
+			Host: cfg.SMTPHost,
+			Port: cfg.SMTPPort,
+			User: cfg.SMTPUser,
+			Pass: cfg.SMTPPass,
+			From: cfg.SMTPFrom,
+		})
+	}
+	dispatcher.Register(string(models.ReminderWebhook), notify.NewWebhookChannel(10*time.Second))
+	dispatcher.Register(string(models.ReminderInApp), &notify.InAppChannel{
+		Inbox: service.NewNotificationService(repository.NewNotificationRepository(db)),
+	})
+
+	reminders := scheduler.NewReminderScheduler(
+		repository.NewTxManager(db),
+		cfg.ReminderInterval,
+		cfg.ReminderBatch,
+	)
+	go reminders.Run(ctx)
+
+	webhooks := scheduler.NewWebhookDispatcher(
+		repository.NewWebhookRepository(db),
+		cfg.WebhookTimeout,
+		cfg.WebhookInterval,
+		cfg.WebhookBatch,
+		cfg.WebhookMaxAttempts,
+	)
+	go webhooks.Run(ctx)
+
+	// Side effects of domain events run off the outbox, after the write
+	// that raised them committed.
+	bus := events.NewBus()
+	bus.Subscribe("webhooks", webhooks.Enqueue)
+	jobRepo := repository.NewJobRepository(db)
+	if cfg.SMTPHost != "" {
+		bus.Subscribe("welcome-email", scheduler.WelcomeEmail(jobRepo), models.EventUserCreated)
+	}
+	relay := scheduler.NewEventRelay(
+		repository.NewOutboxRepository(db),
+		bus,
+		cfg.EventRelayInterval,
+		cfg.EventRelayBatch,
+		cfg.EventMaxAttempts,
+	)
+	go relay.Run(ctx)
+
+	// Background jobs; on shutdown the worker stops claiming and lets
+	// running jobs finish before the process exits.
+	worker := jobs.NewWorker(jobRepo, cfg.JobInterval, cfg.JobStaleAfter, cfg.JobDrain)
+	jobOpts := jobs.Options{Concurrency: cfg.JobConcurrency}
+	jobs.Handle(worker, jobs.TypeNotify, jobOpts, jobs.Notify(dispatcher))
+	jobs.Handle(worker, service.JobImport, jobOpts, newImportJobService(db, cfg, blobs).RunJob)
+	workerDone := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(workerDone)
+	}()
+
+	hub := realtime.NewHub()
+	go hub.Listen(ctx, database.DSN(cfg))
+
+	app := routes.NewFiberApp(cfg, db, blobs, hub)
+
+	go func() {
+		<-ctx.Done()
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Printf("shutdown: %v", err)
+		}
+	}()
 
 	addr := fmt.Sprintf(":%d", cfg.AppPort)
 	if err := app.Listen(addr); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
+	<-workerDone
 }
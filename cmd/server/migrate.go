@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/database"
+)
+
+const migrateUsage = "usage: server migrate up | down [STEPS] | status | search-language [LANGUAGE]"
+
+// runMigrate implements `server migrate`: up applies pending migrations,
+// down reverts the latest one (or STEPS of them) and status lists them.
+// search-language rebuilds todo search in LANGUAGE, or SEARCH_LANGUAGE.
+func runMigrate(db *gorm.DB, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(db)
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid STEPS %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := database.MigrateDown(db, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("nothing to revert")
+		}
+		return err
+	case "status":
+		statuses, err := database.MigrationStatuses(db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05 -0700")
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	case "search-language":
+		language := cfg.SearchLanguage
+		if len(args) > 1 {
+			language = args[1]
+		}
+		changed, err := database.SetSearchLanguage(db, language)
+		if err == nil && !changed {
+			fmt.Printf("search already uses %s\n", language)
+		} else if err == nil {
+			fmt.Printf("search rebuilt with %s\n", language)
+		}
+		return err
+	}
+	return errors.New(migrateUsage)
+}
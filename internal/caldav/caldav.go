@@ -0,0 +1,229 @@
+// Package caldav holds the WebDAV/CalDAV (RFC 4918, 4791, 6578) XML used by
+// the CalDAV endpoint: parsing PROPFIND and REPORT bodies and building
+// multistatus responses. Request routing and storage live in the handlers
+// and services.
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// XML namespaces.
+const (
+	NSDAV    = "DAV:"
+	NSCalDAV = "urn:ietf:params:xml:ns:caldav"
+	NSCS     = "http://calendarserver.org/ns/"
+)
+
+// Report and request element names.
+var (
+	PropFind         = xml.Name{Space: NSDAV, Local: "propfind"}
+	CalendarQuery    = xml.Name{Space: NSCalDAV, Local: "calendar-query"}
+	CalendarMultiget = xml.Name{Space: NSCalDAV, Local: "calendar-multiget"}
+	SyncCollection   = xml.Name{Space: NSDAV, Local: "sync-collection"}
+)
+
+// MaxBody bounds request bodies read by Parse.
+const MaxBody = 1 << 20
+
+// Request is the interesting part of a PROPFIND or REPORT body.
+type Request struct {
+	Root      xml.Name
+	Props     []xml.Name
+	AllProp   bool
+	Hrefs     []string
+	SyncToken string
+	// Components lists the comp-filter names of a calendar-query, outermost
+	// first (e.g. VCALENDAR, VTODO).
+	Components []string
+}
+
+// Wants reports whether the request asks for prop, treating allprop and
+// an empty propfind as asking for everything.
+func (r *Request) Wants(prop xml.Name) bool {
+	if r.AllProp || len(r.Props) == 0 {
+		return true
+	}
+	for _, p := range r.Props {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse reads a request body. An empty body is an allprop PROPFIND.
+func Parse(body io.Reader) (*Request, error) {
+	dec := xml.NewDecoder(io.LimitReader(body, MaxBody))
+	req := &Request{}
+	var stack []xml.Name
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML body: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				req.Root = t.Name
+			}
+			parent := xml.Name{}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			switch {
+			case parent == (xml.Name{Space: NSDAV, Local: "prop"}) && len(stack) == 2:
+				req.Props = append(req.Props, t.Name)
+			case t.Name == (xml.Name{Space: NSDAV, Local: "allprop"}):
+				req.AllProp = true
+			case t.Name == (xml.Name{Space: NSCalDAV, Local: "comp-filter"}):
+				for _, a := range t.Attr {
+					if a.Name.Local == "name" {
+						req.Components = append(req.Components, strings.ToUpper(a.Value))
+					}
+				}
+			}
+			stack = append(stack, t.Name)
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			switch t.Name {
+			case xml.Name{Space: NSDAV, Local: "href"}:
+				req.Hrefs = append(req.Hrefs, strings.TrimSpace(text.String()))
+			case xml.Name{Space: NSDAV, Local: "sync-token"}:
+				req.SyncToken = strings.TrimSpace(text.String())
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if req.Root == (xml.Name{}) {
+		req.Root = PropFind
+		req.AllProp = true
+	}
+	return req, nil
+}
+
+// Property is a property element with text or child elements.
+type Property struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []Property
+}
+
+// Prop builds a property in the DAV: namespace.
+func Prop(local string, children ...Property) Property {
+	return Property{XMLName: xml.Name{Space: NSDAV, Local: local}, Children: children}
+}
+
+// CalProp builds a property in the CalDAV namespace.
+func CalProp(local string, children ...Property) Property {
+	return Property{XMLName: xml.Name{Space: NSCalDAV, Local: local}, Children: children}
+}
+
+// Text builds a property with a text value.
+func Text(name xml.Name, text string) Property {
+	return Property{XMLName: name, Text: text}
+}
+
+// Href builds a DAV:href.
+func Href(href string) Property {
+	return Text(xml.Name{Space: NSDAV, Local: "href"}, href)
+}
+
+type propstat struct {
+	Prop   propList `xml:"DAV: prop"`
+	Status string   `xml:"DAV: status"`
+}
+
+type propList struct {
+	Props []Property
+}
+
+// Response is one DAV:response. Found properties are reported with 200
+// and requested but missing ones with 404.
+type Response struct {
+	XMLName   xml.Name   `xml:"DAV: response"`
+	Href      string     `xml:"DAV: href"`
+	Propstats []propstat `xml:"DAV: propstat,omitempty"`
+	Status    string     `xml:"DAV: status,omitempty"`
+}
+
+// NewResponse answers req for href from the available properties.
+func NewResponse(req *Request, href string, available []Property) Response {
+	resp := Response{Href: href}
+	var found, missing []Property
+	if req.AllProp || len(req.Props) == 0 {
+		found = available
+	} else {
+		for _, name := range req.Props {
+			ok := false
+			for _, p := range available {
+				if p.XMLName == name {
+					found, ok = append(found, p), true
+					break
+				}
+			}
+			if !ok {
+				missing = append(missing, Property{XMLName: name})
+			}
+		}
+	}
+	if len(found) > 0 {
+		resp.Propstats = append(resp.Propstats, propstat{Prop: propList{found}, Status: status(http.StatusOK)})
+	}
+	if len(missing) > 0 {
+		resp.Propstats = append(resp.Propstats, propstat{Prop: propList{missing}, Status: status(http.StatusNotFound)})
+	}
+	return resp
+}
+
+// StatusResponse reports a bare status for href, e.g. 404 for a deleted
+// resource in a sync report or a missing one in a multiget.
+func StatusResponse(href string, code int) Response {
+	return Response{Href: href, Status: status(code)}
+}
+
+// Multistatus is a 207 body.
+type Multistatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []Response `xml:"DAV: response"`
+	SyncToken string     `xml:"DAV: sync-token,omitempty"`
+}
+
+// Marshal renders the multistatus with an XML declaration.
+func (m *Multistatus) Marshal() ([]byte, error) {
+	b, err := xml.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// Error renders a DAV:error body with a single precondition element, such
+// as DAV:valid-sync-token.
+func Error(precondition xml.Name) []byte {
+	b, _ := xml.Marshal(struct {
+		XMLName xml.Name `xml:"DAV: error"`
+		Cond    Property
+	}{Cond: Property{XMLName: precondition}})
+	return append([]byte(xml.Header), b...)
+}
+
+func status(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// ErrUnsupportedReport is returned for REPORT bodies other than
+// calendar-query, calendar-multiget and sync-collection.
+var ErrUnsupportedReport = errors.New("unsupported report")
@@ -1,11 +1,17 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
+
+	"golang.org/x/crypto/hkdf"
 )
 
 type Config struct {
@@ -21,11 +27,57 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	DBTimezone string
+	// DBAutoMigrate applies pending migrations when the server starts.
+	DBAutoMigrate bool
+
+	// SearchLanguage is the Postgres text search configuration used for
+	// todo search, e.g. simple, english or indonesian.
+	SearchLanguage string
 
 	JWTSecret       string
 	JWTExpireMinute int
 
 	UploadDir string
+
+	UploadsSigned   bool
+	UploadURLSecret string
+	UploadURLTTL    time.Duration
+
+	StorageDriver string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PathStyle   bool
+
+	AttachmentMaxBytes     int64
+	AttachmentMaxPerTodo   int
+	AttachmentMaxTodoBytes int64
+	AttachmentAllowedTypes []string
+
+	ReminderInterval time.Duration
+	ReminderBatch    int
+
+	WebhookInterval    time.Duration
+	WebhookBatch       int
+	WebhookMaxAttempts int
+	WebhookTimeout     time.Duration
+
+	EventRelayInterval time.Duration
+	EventRelayBatch    int
+	EventMaxAttempts   int
+
+	JobInterval    time.Duration
+	JobStaleAfter  time.Duration
+	JobDrain       time.Duration
+	JobConcurrency int
+
+	SMTPHost string
+	SMTPPort int
+	SMTPUser string
+	SMTPPass string
+	SMTPFrom string
 }
 
 func getenv(key, fallback string) string {
@@ -45,10 +97,35 @@ func atoi(envKey string, fallback int) int {
 	return fallback
 }
 
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 func durationFromSeconds(envKey string, fallback int) time.Duration {
 	return time.Duration(atoi(envKey, fallback)) * time.Second
 }
 
+// uploadURLKeyInfo is the HKDF label of the upload URL key derived from
+// JWT_SECRET when UPLOAD_URL_SECRET is not set. Changing it invalidates
+// every signed URL handed out.
+const uploadURLKeyInfo = "go-fiber-gorm-todo/upload-url-signing/v1"
+
+// deriveKey derives a 256-bit subkey of secret for the purpose named by
+// info (HKDF-SHA256, RFC 5869), hex-encoded.
+func deriveKey(secret, info string) string {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte(info)), key); err != nil {
+		panic(err) // only fails past 255 blocks of output
+	}
+	return hex.EncodeToString(key)
+}
+
 func ensureDir(path string) {
 	if err := os.MkdirAll(path, 0o755); err != nil {
 		log.Printf("warn: cannot create dir %s: %v", path, err)
@@ -70,10 +147,61 @@ func Load() *Config {
 		DBSSLMode:  getenv("DB_SSLMODE", "disable"),
 		DBTimezone: getenv("DB_TIMEZONE", "Asia/Jakarta"),
 
+		DBAutoMigrate: getenv("DB_AUTO_MIGRATE", "true") == "true",
+
+		SearchLanguage: getenv("SEARCH_LANGUAGE", "simple"),
+
 		JWTSecret:       getenv("JWT_SECRET", "supersecretchangeme"),
 		JWTExpireMinute: atoi("JWT_EXPIRE_MINUTES", 60),
 
 		UploadDir: getenv("UPLOAD_DIR", "./uploads"),
+
+		UploadsSigned:   getenv("UPLOADS_SIGNED", "false") == "true",
+		UploadURLSecret: getenv("UPLOAD_URL_SECRET", ""),
+		UploadURLTTL:    durationFromSeconds("UPLOAD_URL_TTL", 3600),
+
+		StorageDriver: getenv("STORAGE_DRIVER", "local"),
+		S3Endpoint:    getenv("S3_ENDPOINT", ""),
+		S3Region:      getenv("S3_REGION", "us-east-1"),
+		S3Bucket:      getenv("S3_BUCKET", ""),
+		S3AccessKey:   getenv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getenv("S3_SECRET_KEY", ""),
+		S3PathStyle:   getenv("S3_PATH_STYLE", "true") == "true",
+
+		AttachmentMaxBytes:     int64(atoi("ATTACHMENT_MAX_BYTES", 10<<20)),
+		AttachmentMaxPerTodo:   atoi("ATTACHMENT_MAX_PER_TODO", 20),
+		AttachmentMaxTodoBytes: int64(atoi("ATTACHMENT_MAX_TODO_BYTES", 50<<20)),
+		AttachmentAllowedTypes: splitList(getenv("ATTACHMENT_ALLOWED_TYPES",
+			"image/*,application/pdf,text/plain,text/csv,application/zip")),
+
+		ReminderInterval: durationFromSeconds("REMINDER_POLL_SECONDS", 30),
+		ReminderBatch:    atoi("REMINDER_BATCH", 50),
+
+		WebhookInterval:    durationFromSeconds("WEBHOOK_POLL_SECONDS", 5),
+		WebhookBatch:       atoi("WEBHOOK_BATCH", 20),
+		WebhookMaxAttempts: atoi("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:     durationFromSeconds("WEBHOOK_TIMEOUT_SECONDS", 10),
+
+		EventRelayInterval: durationFromSeconds("EVENT_RELAY_POLL_SECONDS", 2),
+		EventRelayBatch:    atoi("EVENT_RELAY_BATCH", 100),
+		EventMaxAttempts:   atoi("EVENT_MAX_ATTEMPTS", 10),
+
+		JobInterval:    durationFromSeconds("JOB_POLL_SECONDS", 1),
+		JobStaleAfter:  durationFromSeconds("JOB_STALE_SECONDS", 900),
+		JobDrain:       durationFromSeconds("JOB_DRAIN_SECONDS", 30),
+		JobConcurrency: atoi("JOB_CONCURRENCY", 2),
+
+		SMTPHost: getenv("SMTP_HOST", ""),
+		SMTPPort: atoi("SMTP_PORT", 587),
+		SMTPUser: getenv("SMTP_USER", ""),
+		SMTPPass: getenv("SMTP_PASS", ""),
+		SMTPFrom: getenv("SMTP_FROM", "no-reply@example.com"),
+	}
+
+	// Never sign upload URLs with the JWT key itself: a key shared between
+	// the two would let anything that verifies one forge the other.
+	if cfg.UploadURLSecret == "" {
+		cfg.UploadURLSecret = deriveKey(cfg.JWTSecret, uploadURLKeyInfo)
 	}
 
 	// Normalize relative path
@@ -2,30 +2,53 @@ package database
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
-	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-func Connect(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
+// DSN is the connection string for cfg's database.
+func DSN(cfg *config.Config) string {
+	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
 		cfg.DBHost, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBPort, cfg.DBSSLMode, cfg.DBTimezone,
 	)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+}
+
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(DSN(cfg)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Warn),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Auto-migrate models
-	if err := db.AutoMigrate(&models.User{}, &models.Todo{}); err != nil {
-		return nil, err
-	}
-
 	return db, nil
 }
+
+// Prepare readies a database for serving: it applies pending migrations
+// when cfg allows it, and warns when the search column was built in
+// another language than SEARCH_LANGUAGE. Switching languages rebuilds a
+// column and an index, so it is left to `migrate search-language`.
+func Prepare(db *gorm.DB, cfg *config.Config) error {
+	if cfg.DBAutoMigrate {
+		applied, err := MigrateUp(db)
+		if err != nil {
+			return err
+		}
+		for _, m := range applied {
+			log.Printf("migrated %04d_%s", m.Version, m.Name)
+		}
+	}
+	current, err := SearchLanguage(db)
+	if err != nil {
+		return err
+	}
+	if current != "" && current != cfg.SearchLanguage {
+		log.Printf("search uses %q but SEARCH_LANGUAGE is %q; run `migrate search-language` to switch", current, cfg.SearchLanguage)
+	}
+	return nil
+}
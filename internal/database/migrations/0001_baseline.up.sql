@@ -0,0 +1,27 @@
+-- Schema of the first release, as AutoMigrate created it: users and todos
+-- only. IF NOT EXISTS lets a database set up by that release adopt this
+-- migration unchanged; everything added since is a later migration.
+
+CREATE TABLE IF NOT EXISTS users (
+	id            bigserial PRIMARY KEY,
+	name          varchar(120) NOT NULL,
+	email         varchar(180) NOT NULL,
+	password_hash varchar(255) NOT NULL,
+	role          varchar(20) DEFAULT 'user',
+	avatar_url    varchar(255),
+	created_at    timestamptz,
+	updated_at    timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS todos (
+	id          bigserial PRIMARY KEY,
+	title       varchar(200) NOT NULL,
+	description text,
+	completed   boolean DEFAULT false,
+	due_date    timestamptz,
+	priority    varchar(10) DEFAULT 'medium',
+	owner_id    bigint,
+	created_at  timestamptz,
+	updated_at  timestamptz
+);
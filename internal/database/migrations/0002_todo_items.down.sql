@@ -0,0 +1,2 @@
+DROP TABLE todo_items;
+ALTER TABLE todos DROP COLUMN auto_complete;
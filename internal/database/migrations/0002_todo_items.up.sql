@@ -0,0 +1,13 @@
+ALTER TABLE todos ADD COLUMN auto_complete boolean DEFAULT false;
+
+CREATE TABLE todo_items (
+	id         bigserial PRIMARY KEY,
+	todo_id    bigint NOT NULL,
+	title      varchar(200) NOT NULL,
+	completed  boolean DEFAULT false,
+	position   bigint NOT NULL DEFAULT 0,
+	created_at timestamptz,
+	updated_at timestamptz,
+	CONSTRAINT fk_todos_items FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_todo_items_todo_id ON todo_items (todo_id);
@@ -0,0 +1,5 @@
+ALTER TABLE todos
+	DROP COLUMN occurrence,
+	DROP COLUMN series_id,
+	DROP COLUMN r_rule;
+ALTER TABLE users DROP COLUMN timezone;
@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN timezone varchar(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE todos
+	ADD COLUMN r_rule     varchar(255),
+	ADD COLUMN series_id  bigint,
+	ADD COLUMN occurrence bigint NOT NULL DEFAULT 1;
+CREATE INDEX idx_todos_series_id ON todos (series_id);
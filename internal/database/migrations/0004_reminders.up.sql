@@ -0,0 +1,19 @@
+CREATE TABLE reminders (
+	id             bigserial PRIMARY KEY,
+	todo_id        bigint NOT NULL,
+	user_id        bigint NOT NULL,
+	remind_at      timestamptz,
+	offset_minutes bigint,
+	fire_at        timestamptz,
+	channel        varchar(20) NOT NULL DEFAULT 'email',
+	target         varchar(500),
+	fired_at       timestamptz,
+	last_error     varchar(500),
+	created_at     timestamptz,
+	updated_at     timestamptz,
+	CONSTRAINT fk_todos_reminders FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_reminders_todo_id ON reminders (todo_id);
+CREATE INDEX idx_reminders_user_id ON reminders (user_id);
+CREATE INDEX idx_reminders_fire_at ON reminders (fire_at);
+CREATE INDEX idx_reminders_fired_at ON reminders (fired_at);
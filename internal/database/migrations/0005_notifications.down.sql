@@ -0,0 +1,2 @@
+ALTER TABLE reminders ALTER COLUMN channel SET DEFAULT 'email';
+DROP TABLE notifications;
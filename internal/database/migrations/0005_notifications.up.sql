@@ -0,0 +1,15 @@
+CREATE TABLE notifications (
+	id         bigserial PRIMARY KEY,
+	user_id    bigint NOT NULL,
+	type       varchar(30) NOT NULL,
+	title      varchar(200) NOT NULL,
+	body       text,
+	todo_id    bigint,
+	read_at    timestamptz,
+	created_at timestamptz
+);
+CREATE INDEX idx_notifications_user_read ON notifications (user_id, read_at);
+CREATE INDEX idx_notifications_todo_id ON notifications (todo_id);
+
+-- Reminders are delivered in-app unless another channel is asked for.
+ALTER TABLE reminders ALTER COLUMN channel SET DEFAULT 'inapp';
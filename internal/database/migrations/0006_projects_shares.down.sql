@@ -0,0 +1,3 @@
+DROP TABLE shares;
+ALTER TABLE todos DROP COLUMN project_id;
+DROP TABLE projects;
@@ -0,0 +1,28 @@
+CREATE TABLE projects (
+	id         bigserial PRIMARY KEY,
+	name       varchar(120) NOT NULL,
+	owner_id   bigint NOT NULL,
+	created_at timestamptz,
+	updated_at timestamptz
+);
+CREATE INDEX idx_projects_owner_id ON projects (owner_id);
+
+ALTER TABLE todos ADD COLUMN project_id bigint
+	CONSTRAINT fk_projects_todos REFERENCES projects (id) ON DELETE SET NULL;
+CREATE INDEX idx_todos_project_id ON todos (project_id);
+
+CREATE TABLE shares (
+	id         bigserial PRIMARY KEY,
+	todo_id    bigint,
+	project_id bigint,
+	user_id    bigint NOT NULL,
+	permission varchar(10) NOT NULL DEFAULT 'viewer',
+	created_by bigint,
+	created_at timestamptz,
+	CONSTRAINT fk_todos_shares FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE,
+	CONSTRAINT fk_projects_shares FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+	CONSTRAINT fk_shares_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_shares_todo_user ON shares (todo_id, user_id);
+CREATE UNIQUE INDEX idx_shares_project_user ON shares (project_id, user_id);
+CREATE INDEX idx_shares_user_id ON shares (user_id);
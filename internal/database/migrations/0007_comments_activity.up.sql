@@ -0,0 +1,26 @@
+CREATE TABLE comments (
+	id         bigserial PRIMARY KEY,
+	todo_id    bigint NOT NULL,
+	author_id  bigint NOT NULL,
+	body       text NOT NULL,
+	edited_at  timestamptz,
+	created_at timestamptz,
+	updated_at timestamptz,
+	CONSTRAINT fk_todos_comments FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE,
+	CONSTRAINT fk_comments_author FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_comments_todo_id ON comments (todo_id);
+CREATE INDEX idx_comments_author_id ON comments (author_id);
+
+CREATE TABLE todo_activities (
+	id         bigserial PRIMARY KEY,
+	todo_id    bigint NOT NULL,
+	actor_id   bigint NOT NULL,
+	field      varchar(50) NOT NULL,
+	old_value  text,
+	new_value  text,
+	created_at timestamptz,
+	CONSTRAINT fk_todos_activities FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_todo_activities_todo_id ON todo_activities (todo_id);
+CREATE INDEX idx_todo_activities_created_at ON todo_activities (created_at);
@@ -0,0 +1,14 @@
+CREATE TABLE attachments (
+	id           bigserial PRIMARY KEY,
+	todo_id      bigint NOT NULL,
+	uploader_id  bigint NOT NULL,
+	filename     varchar(255) NOT NULL,
+	content_type varchar(255) NOT NULL,
+	size         bigint NOT NULL,
+	storage_key  varchar(512) NOT NULL,
+	created_at   timestamptz,
+	CONSTRAINT fk_todos_attachments FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_attachments_todo_id ON attachments (todo_id);
+CREATE INDEX idx_attachments_uploader_id ON attachments (uploader_id);
+CREATE UNIQUE INDEX idx_attachments_storage_key ON attachments (storage_key);
@@ -0,0 +1,14 @@
+CREATE TABLE tags (
+	id         bigserial PRIMARY KEY,
+	name       varchar(50) NOT NULL,
+	created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_tags_name ON tags (name);
+
+CREATE TABLE todo_tags (
+	todo_id bigint,
+	tag_id  bigint,
+	PRIMARY KEY (todo_id, tag_id),
+	CONSTRAINT fk_todo_tags_todo FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE,
+	CONSTRAINT fk_todo_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
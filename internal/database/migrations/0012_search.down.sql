@@ -0,0 +1,2 @@
+ALTER TABLE todos DROP COLUMN search_vector;
+DROP FUNCTION todo_search_config();
@@ -0,0 +1,13 @@
+-- Full-text search over todo titles (weight A) and descriptions (weight
+-- B). Queries use todo_search_config() so they always agree with the
+-- column; `server migrate search-language` switches both to another text
+-- search configuration. The column comment records the current one.
+CREATE FUNCTION todo_search_config() RETURNS regconfig
+	LANGUAGE sql IMMUTABLE AS $$ SELECT 'simple'::regconfig $$;
+
+ALTER TABLE todos ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple'::regconfig, coalesce(title, '')), 'A') ||
+	setweight(to_tsvector('simple'::regconfig, coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX idx_todos_search_vector ON todos USING GIN (search_vector);
+COMMENT ON COLUMN todos.search_vector IS 'simple';
@@ -0,0 +1,13 @@
+CREATE TABLE saved_views (
+	id             bigserial PRIMARY KEY,
+	owner_id       bigint NOT NULL,
+	name           varchar(120) NOT NULL,
+	query          varchar(200),
+	filter         varchar(1000),
+	sort           varchar(20),
+	project_id     bigint,
+	include_shared boolean NOT NULL DEFAULT false,
+	created_at     timestamptz,
+	updated_at     timestamptz
+);
+CREATE INDEX idx_saved_views_owner_id ON saved_views (owner_id);
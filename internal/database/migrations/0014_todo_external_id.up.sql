@@ -0,0 +1,2 @@
+ALTER TABLE todos ADD COLUMN external_id varchar(255);
+CREATE UNIQUE INDEX idx_todos_owner_external ON todos (owner_id, external_id);
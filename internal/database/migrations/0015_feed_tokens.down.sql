@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN feed_token_hash;
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN feed_token_hash varchar(64);
+CREATE UNIQUE INDEX idx_users_feed_token_hash ON users (feed_token_hash);
@@ -0,0 +1,17 @@
+CREATE TABLE import_jobs (
+	id          bigserial PRIMARY KEY,
+	owner_id    bigint NOT NULL,
+	source      varchar(20) NOT NULL,
+	file_name   varchar(255),
+	dry_run     boolean NOT NULL DEFAULT false,
+	status      varchar(20) NOT NULL,
+	error       text,
+	payload     bytea,
+	report      jsonb,
+	started_at  timestamptz,
+	finished_at timestamptz,
+	created_at  timestamptz,
+	updated_at  timestamptz
+);
+CREATE INDEX idx_import_jobs_owner_id ON import_jobs (owner_id);
+CREATE INDEX idx_import_jobs_status ON import_jobs (status);
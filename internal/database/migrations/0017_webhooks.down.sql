@@ -0,0 +1 @@
+DROP TABLE webhook_attempts, webhook_deliveries, outbox_events, webhooks;
@@ -0,0 +1,56 @@
+CREATE TABLE webhooks (
+	id          bigserial PRIMARY KEY,
+	owner_id    bigint NOT NULL,
+	url         varchar(2048) NOT NULL,
+	description varchar(200),
+	events      jsonb NOT NULL,
+	global      boolean NOT NULL DEFAULT false,
+	active      boolean NOT NULL DEFAULT true,
+	secret      varchar(100) NOT NULL,
+	created_at  timestamptz,
+	updated_at  timestamptz
+);
+CREATE INDEX idx_webhooks_owner_id ON webhooks (owner_id);
+
+CREATE TABLE outbox_events (
+	id           bigserial PRIMARY KEY,
+	type         varchar(50) NOT NULL,
+	user_id      bigint NOT NULL,
+	payload      jsonb NOT NULL,
+	created_at   timestamptz,
+	processed_at timestamptz
+);
+CREATE INDEX idx_outbox_events_user_id ON outbox_events (user_id);
+CREATE INDEX idx_outbox_events_processed_at ON outbox_events (processed_at);
+
+CREATE TABLE webhook_deliveries (
+	id               bigserial PRIMARY KEY,
+	webhook_id       bigint NOT NULL,
+	event_id         bigint NOT NULL,
+	event_type       varchar(50) NOT NULL,
+	payload          jsonb NOT NULL,
+	status           varchar(20) NOT NULL,
+	attempts         bigint NOT NULL DEFAULT 0,
+	next_attempt_at  timestamptz,
+	last_status_code bigint,
+	last_error       text,
+	delivered_at     timestamptz,
+	created_at       timestamptz,
+	updated_at       timestamptz,
+	CONSTRAINT fk_webhooks_deliveries FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+
+CREATE TABLE webhook_attempts (
+	id          bigserial PRIMARY KEY,
+	delivery_id bigint NOT NULL,
+	status_code bigint,
+	error       text,
+	response    text,
+	duration_ms bigint,
+	created_at  timestamptz,
+	CONSTRAINT fk_webhook_deliveries_logs FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_webhook_attempts_delivery_id ON webhook_attempts (delivery_id);
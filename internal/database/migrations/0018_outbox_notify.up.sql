@@ -0,0 +1,13 @@
+-- Every new outbox event NOTIFYs the outbox_events channel (realtime.Channel)
+-- with its id, user_id and type. NOTIFY is transactional, so listeners
+-- only hear about events that committed.
+CREATE FUNCTION notify_outbox_event() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+	PERFORM pg_notify('outbox_events',
+		json_build_object('id', NEW.id, 'user_id', NEW.user_id, 'type', NEW.type)::text);
+	RETURN NEW;
+END $$;
+
+CREATE TRIGGER outbox_events_notify AFTER INSERT ON outbox_events
+	FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
@@ -0,0 +1,5 @@
+DROP INDEX idx_webhook_deliveries_event;
+ALTER TABLE outbox_events
+	DROP COLUMN last_error,
+	DROP COLUMN next_attempt_at,
+	DROP COLUMN attempts;
@@ -0,0 +1,6 @@
+ALTER TABLE outbox_events
+	ADD COLUMN attempts        bigint NOT NULL DEFAULT 0,
+	ADD COLUMN next_attempt_at timestamptz,
+	ADD COLUMN last_error      text;
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id);
@@ -0,0 +1,17 @@
+CREATE TABLE jobs (
+	id           bigserial PRIMARY KEY,
+	type         varchar(50) NOT NULL,
+	payload      jsonb NOT NULL,
+	status       varchar(20) NOT NULL,
+	unique_key   varchar(200),
+	run_at       timestamptz NOT NULL,
+	attempts     bigint NOT NULL DEFAULT 0,
+	max_attempts bigint NOT NULL DEFAULT 5,
+	last_error   text,
+	locked_at    timestamptz,
+	finished_at  timestamptz,
+	created_at   timestamptz,
+	updated_at   timestamptz
+);
+CREATE INDEX idx_jobs_due ON jobs (status, type, run_at);
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs (unique_key);
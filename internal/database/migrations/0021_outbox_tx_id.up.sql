@@ -0,0 +1,6 @@
+-- tx_id is the transaction that recorded the event. Event streams are
+-- read in (tx_id, id) order and stop at transactions still running, which
+-- id order alone cannot do: ids are assigned at insert, not at commit.
+ALTER TABLE outbox_events ADD COLUMN tx_id bigint NOT NULL DEFAULT (pg_current_xact_id()::text::bigint);
+CREATE INDEX idx_outbox_events_tx ON outbox_events (tx_id, id);
+CREATE INDEX idx_outbox_events_user_tx ON outbox_events (user_id, tx_id, id);
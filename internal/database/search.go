@@ -0,0 +1,76 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+var searchConfigName = regexp.MustCompile(`^[a-z_]+$`)
+
+// SearchLanguage returns the text search configuration the search_vector
+// column of todos is built with, or "" when the search migration has not
+// been applied yet.
+func SearchLanguage(db *gorm.DB) (string, error) {
+	var current *string
+	err := db.Raw(`SELECT col_description('todos'::regclass, attnum) FROM pg_attribute
+		WHERE attrelid = 'todos'::regclass AND attname = 'search_vector' AND NOT attisdropped`).Scan(&current).Error
+	if err != nil || current == nil {
+		return "", err
+	}
+	return *current, nil
+}
+
+// SetSearchLanguage rebuilds the full-text search column of todos and its
+// GIN index with another text search configuration (language), and points
+// todo_search_config() at it so queries keep agreeing with the column. It
+// runs under the migration lock and reports whether anything changed.
+func SetSearchLanguage(db *gorm.DB, language string) (bool, error) {
+	if !searchConfigName.MatchString(language) {
+		return false, fmt.Errorf("invalid search language %q", language)
+	}
+	changed := false
+	err := withMigrationLock(db, func(conn *gorm.DB, applied map[int]bool) error {
+		var exists bool
+		if err := conn.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = ?)", language).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("text search configuration %q does not exist", language)
+		}
+		current, err := SearchLanguage(conn)
+		if err != nil {
+			return err
+		}
+		if current == "" {
+			return errors.New("search column missing; run `migrate up` first")
+		}
+		if current == language {
+			return nil
+		}
+
+		changed = true
+		return conn.Transaction(func(tx *gorm.DB) error {
+			stmts := []string{
+				fmt.Sprintf(`CREATE OR REPLACE FUNCTION todo_search_config() RETURNS regconfig
+					LANGUAGE sql IMMUTABLE AS $$ SELECT '%s'::regconfig $$`, language),
+				`ALTER TABLE todos DROP COLUMN search_vector`,
+				fmt.Sprintf(`ALTER TABLE todos ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+					setweight(to_tsvector('%[1]s'::regconfig, coalesce(title, '')), 'A') ||
+					setweight(to_tsvector('%[1]s'::regconfig, coalesce(description, '')), 'B')
+				) STORED`, language),
+				`CREATE INDEX idx_todos_search_vector ON todos USING GIN (search_vector)`,
+				fmt.Sprintf(`COMMENT ON COLUMN todos.search_vector IS '%s'`, language),
+			}
+			for _, stmt := range stmts {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	return changed, err
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// Handler reacts to a recorded event. Returning an error makes the relay
+// retry the event later, so handlers must be idempotent.
+type Handler func(ctx context.Context, e *models.OutboxEvent) error
+
+type subscriber struct {
+	name    string
+	types   map[string]bool
+	handler Handler
+}
+
+// Bus routes recorded events to the subscribers of their type.
+type Bus struct {
+	mu   sync.RWMutex
+	subs []subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers h under name for the given event types, or for
+// every event when none are given.
+func (b *Bus) Subscribe(name string, h Handler, types ...string) {
+	s := subscriber{name: name, handler: h}
+	if len(types) > 0 {
+		s.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+}
+
+// Dispatch hands e to every matching subscriber, even when one of them
+// fails, and returns their errors joined.
+func (b *Bus) Dispatch(ctx context.Context, e *models.OutboxEvent) error {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+	var errs []error
+	for _, s := range subs {
+		if s.types != nil && !s.types[e.Type] {
+			continue
+		}
+		if err := s.handler(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+// Package events defines the domain events raised by writes and the
+// in-process bus that hands them to subscribers.
+//
+// Services publish events into the outbox table through the same
+// transaction as the change that raised them, so an event exists exactly
+// when its change committed. A relay worker later reads the outbox and
+// dispatches each event to the bus, retrying until every subscriber has
+// handled it. Delivery is at-least-once: subscribers must tolerate seeing
+// an event again.
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// Event is something that happened to a user's data.
+type Event interface {
+	// EventType is the wire name, e.g. "todo.created".
+	EventType() string
+	// EventUserID is the user whose data changed.
+	EventUserID() uint
+	// EventData is encoded as the event's JSON payload.
+	EventData() interface{}
+}
+
+type TodoCreated struct{ Todo *models.Todo }
+
+func (e TodoCreated) EventType() string      { return models.EventTodoCreated }
+func (e TodoCreated) EventUserID() uint      { return e.Todo.OwnerID }
+func (e TodoCreated) EventData() interface{} { return e.Todo }
+
+type TodoUpdated struct{ Todo *models.Todo }
+
+func (e TodoUpdated) EventType() string      { return models.EventTodoUpdated }
+func (e TodoUpdated) EventUserID() uint      { return e.Todo.OwnerID }
+func (e TodoUpdated) EventData() interface{} { return e.Todo }
+
+// TodoCompleted follows the TodoUpdated of a change that completed a todo.
+type TodoCompleted struct{ Todo *models.Todo }
+
+func (e TodoCompleted) EventType() string      { return models.EventTodoCompleted }
+func (e TodoCompleted) EventUserID() uint      { return e.Todo.OwnerID }
+func (e TodoCompleted) EventData() interface{} { return e.Todo }
+
+// TodoDeleted only carries what identifies the todo, which is gone by the
+// time subscribers see it.
+type TodoDeleted struct {
+	ID        uint   `json:"id"`
+	OwnerID   uint   `json:"owner_id"`
+	ProjectID *uint  `json:"project_id,omitempty"`
+	Title     string `json:"title"`
+}
+
+func (e TodoDeleted) EventType() string      { return models.EventTodoDeleted }
+func (e TodoDeleted) EventUserID() uint      { return e.OwnerID }
+func (e TodoDeleted) EventData() interface{} { return e }
+
+func TodoDeletedOf(todo *models.Todo) TodoDeleted {
+	return TodoDeleted{ID: todo.ID, OwnerID: todo.OwnerID, ProjectID: todo.ProjectID, Title: todo.Title}
+}
+
+// UserRegistered keeps the "user.created" wire name webhooks subscribe to.
+type UserRegistered struct{ User *models.User }
+
+func (e UserRegistered) EventType() string      { return models.EventUserCreated }
+func (e UserRegistered) EventUserID() uint      { return e.User.ID }
+func (e UserRegistered) EventData() interface{} { return e.User }
+
+type UserUpdated struct{ User *models.User }
+
+func (e UserUpdated) EventType() string      { return models.EventUserUpdated }
+func (e UserUpdated) EventUserID() uint      { return e.User.ID }
+func (e UserUpdated) EventData() interface{} { return e.User }
+
+// Record encodes events as outbox rows.
+func Record(evs ...Event) ([]models.OutboxEvent, error) {
+	out := make([]models.OutboxEvent, 0, len(evs))
+	for _, e := range evs {
+		payload, err := json.Marshal(e.EventData())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, models.OutboxEvent{Type: e.EventType(), UserID: e.EventUserID(), Payload: payload})
+	}
+	return out, nil
+}
@@ -0,0 +1,151 @@
+// Package filter parses the filter expressions accepted by list endpoints,
+// e.g.
+//
+//	priority in (high, medium) and due_date < now+7d and not completed
+//
+// into an AST that is checked against a whitelist of fields and rendered
+// as a parameterised SQL condition. Field names and operators never reach
+// the SQL verbatim; values are always bound as arguments.
+//
+// Grammar:
+//
+//	expr       = term { "or" term }
+//	term       = factor { "and" factor }
+//	factor     = "not" factor | "(" expr ")" | predicate
+//	predicate  = field [ op value | [ "not" ] "in" "(" value { "," value } ")" | "is" [ "not" ] "null" ]
+//	op         = "=" | "!=" | "<" | "<=" | ">" | ">=" | "~"
+//
+// A bare boolean field is shorthand for field = true. "~" is a
+// case-insensitive substring match. Time values are dates (2024-05-01),
+// RFC 3339 timestamps or now/today with an optional offset such as now+7d,
+// today-1w or now+90m (units m, h, d, w).
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Limits on accepted expressions.
+const (
+	MaxLength = 1000
+	MaxDepth  = 20
+	MaxValues = 100
+)
+
+type Kind int
+
+const (
+	Text Kind = iota
+	Bool
+	Enum
+	Int
+	Time
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Bool:
+		return "boolean"
+	case Enum:
+		return "enum"
+	case Int:
+		return "integer"
+	case Time:
+		return "time"
+	}
+	return "text"
+}
+
+// Field describes a filterable field. Column is the SQL it maps to. When
+// Wrap is set the predicate is rendered into it (a format string with a
+// single %s), which is how fields backed by another table, such as tags,
+// become EXISTS subqueries; such fields only support positive matches
+// and are negated with "not".
+type Field struct {
+	Column   string
+	Kind     Kind
+	Values   []string
+	Nullable bool
+	Wrap     string
+}
+
+// Fields is the whitelist of fields an expression may reference, keyed by
+// the name used in expressions.
+type Fields map[string]Field
+
+// Error is a syntax or validation error at a position in the expression.
+type Error struct {
+	Pos  int
+	Near string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Near == "" {
+		return fmt.Sprintf("filter: %s at end of expression", e.Msg)
+	}
+	return fmt.Sprintf("filter: %s at position %d near %q", e.Msg, e.Pos+1, e.Near)
+}
+
+func errorAt(pos int, near, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Near: near, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Node is a parsed expression.
+type Node interface {
+	// SQL renders the node as a condition with ? placeholders.
+	SQL() (string, []interface{})
+}
+
+type binary struct {
+	op   string
+	l, r Node
+}
+
+func (n binary) SQL() (string, []interface{}) {
+	ls, la := n.l.SQL()
+	rs, ra := n.r.SQL()
+	return "(" + ls + " " + n.op + " " + rs + ")", append(la, ra...)
+}
+
+type not struct {
+	x Node
+}
+
+func (n not) SQL() (string, []interface{}) {
+	s, a := n.x.SQL()
+	return "NOT (" + s + ")", a
+}
+
+// predicate compares a field with values. op is the SQL operator; for
+// IN the values are bound as a single list argument and for IS NULL there
+// are none.
+type predicate struct {
+	field  Field
+	op     string
+	values []interface{}
+}
+
+func (n predicate) SQL() (string, []interface{}) {
+	var s string
+	var args []interface{}
+	switch n.op {
+	case "IN", "NOT IN":
+		s, args = n.field.Column+" "+n.op+" ?", []interface{}{n.values}
+	case "IS NULL", "IS NOT NULL":
+		s = n.field.Column + " " + n.op
+	default:
+		s, args = n.field.Column+" "+n.op+" ?", n.values
+	}
+	if n.field.Wrap != "" {
+		s = fmt.Sprintf(n.field.Wrap, s)
+	}
+	return s, args
+}
+
+// likePattern turns v into an ILIKE pattern matching it as a substring.
+func likePattern(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(v) + "%"
+}
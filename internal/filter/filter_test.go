@@ -0,0 +1,215 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"title":      {Column: "title", Kind: Text},
+	"completed":  {Column: "completed", Kind: Bool},
+	"priority":   {Column: "priority", Kind: Enum, Values: []string{"low", "medium", "high"}},
+	"due_date":   {Column: "due_date", Kind: Time, Nullable: true},
+	"created_at": {Column: "created_at", Kind: Time},
+	"project_id": {Column: "project_id", Kind: Int, Nullable: true},
+	"tag":        {Column: "tags.name", Kind: Text, Wrap: "EXISTS (SELECT 1 FROM tags WHERE %s)"},
+}
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)
+	today := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		src  string
+		sql  string
+		args []interface{}
+	}{
+		{"empty", "  ", "", nil},
+		{"bare boolean", "completed", "completed = ?", []interface{}{true}},
+		{"not", "not completed", "NOT (completed = ?)", []interface{}{true}},
+		{"double not", "not not completed", "NOT (NOT (completed = ?))", []interface{}{true}},
+		{"and binds tighter than or", "completed or priority = high and project_id = 1",
+			"(completed = ? OR (priority = ? AND project_id = ?))", []interface{}{true, "high", int64(1)}},
+		{"or is left associative", "completed or priority = low or priority = high",
+			"((completed = ? OR priority = ?) OR priority = ?)", []interface{}{true, "low", "high"}},
+		{"parentheses override precedence", "(completed or priority = high) and project_id = 1",
+			"((completed = ? OR priority = ?) AND project_id = ?)", []interface{}{true, "high", int64(1)}},
+		{"not binds tighter than and", "not completed and priority = high",
+			"(NOT (completed = ?) AND priority = ?)", []interface{}{true, "high"}},
+		{"not of a group", "not (completed or priority = high)",
+			"NOT ((completed = ? OR priority = ?))", []interface{}{true, "high"}},
+		{"keywords are case-insensitive", "Completed AND NOT priority = HIGH",
+			"(completed = ? AND NOT (priority = ?))", []interface{}{true, "high"}},
+		{"in", "priority in (high, medium)", "priority IN ?", []interface{}{[]interface{}{"high", "medium"}}},
+		{"not in", "priority not in (low)", "priority NOT IN ?", []interface{}{[]interface{}{"low"}}},
+		{"not equal", "priority != low", "priority IS DISTINCT FROM ?", []interface{}{"low"}},
+		{"is null", "due_date is null", "due_date IS NULL", nil},
+		{"is not null", "project_id is not null", "project_id IS NOT NULL", nil},
+		{"explicit boolean", "completed = false", "completed = ?", []interface{}{false}},
+		{"integer comparison", "project_id >= 10", "project_id >= ?", []interface{}{int64(10)}},
+		{"single-quoted text", `title = 'buy milk'`, "title = ?", []interface{}{"buy milk"}},
+		{"double-quoted text", `title = "say 'hi'"`, "title = ?", []interface{}{"say 'hi'"}},
+		{"escaped quote", `title = 'it\'s'`, "title = ?", []interface{}{"it's"}},
+		{"keyword as quoted value", `title = 'and'`, "title = ?", []interface{}{"and"}},
+		{"contains escapes like wildcards", `title ~ '50%_off\\'`, "title ILIKE ?", []interface{}{`%50\%\_off\\%`}},
+		{"wrapped field", "tag = work", "EXISTS (SELECT 1 FROM tags WHERE tags.name = ?)", []interface{}{"work"}},
+		{"negated wrapped field", "not tag in (work, home)",
+			"NOT (EXISTS (SELECT 1 FROM tags WHERE tags.name IN ?))", []interface{}{[]interface{}{"work", "home"}}},
+		{"date", "due_date < 2024-06-01", "due_date < ?", []interface{}{time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}},
+		{"rfc 3339", "created_at >= 2024-05-01T10:00:00+07:00", "created_at >= ?",
+			[]interface{}{time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)}},
+		{"now", "due_date < now", "due_date < ?", []interface{}{now}},
+		{"now plus days", "due_date < now+7d", "due_date < ?", []interface{}{now.AddDate(0, 0, 7)}},
+		{"today minus weeks", "due_date >= today-1w", "due_date >= ?", []interface{}{today.AddDate(0, 0, -7)}},
+		{"now plus minutes", "due_date <= now+90m", "due_date <= ?", []interface{}{now.Add(90 * time.Minute)}},
+		{"today plus hours", "due_date > TODAY+2h", "due_date > ?", []interface{}{today.Add(2 * time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseAt(tt.src, testFields, now)
+			if err != nil {
+				t.Fatalf("ParseAt(%q): %v", tt.src, err)
+			}
+			if n == nil {
+				if tt.sql != "" {
+					t.Fatalf("ParseAt(%q) = nil", tt.src)
+				}
+				return
+			}
+			sql, args := n.SQL()
+			if sql != tt.sql {
+				t.Errorf("sql = %q, want %q", sql, tt.sql)
+			}
+			if !equalArgs(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+// equalArgs compares bound arguments, times by instant.
+func equalArgs(got, want []interface{}) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if g, ok := got[i].(time.Time); ok {
+			w, ok := want[i].(time.Time)
+			if !ok || !g.Equal(w) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseErrors(t *testing.T) {
+	deep := ""
+	for i := 0; i <= MaxDepth+1; i++ {
+		deep += "("
+	}
+	deep += "completed"
+	for i := 0; i <= MaxDepth+1; i++ {
+		deep += ")"
+	}
+	manyValues := "project_id in (0"
+	for i := 1; i <= MaxValues; i++ {
+		manyValues += ",1"
+	}
+	manyValues += ")"
+	long := "title = '"
+	for len(long) <= MaxLength {
+		long += "x"
+	}
+	long += "'"
+
+	tests := []struct {
+		name string
+		src  string
+		pos  int // 1-based position reported; 0 for end of expression
+	}{
+		{"unknown field", "priority = high and secret = 1", 21},
+		{"field is not an expression", "password_hash", 1},
+		{"unknown operator", "title ! x", 7},
+		{"unexpected character", "title = x;", 10},
+		{"unterminated string", "title = 'milk", 9},
+		{"missing value", "priority =", 0},
+		{"missing operator", "priority high", 10},
+		{"dangling and", "completed and", 0},
+		{"leading or", "or completed", 1},
+		{"unbalanced open", "(completed", 0},
+		{"unbalanced close", "completed)", 10},
+		{"empty group", "()", 2},
+		{"two predicates", "completed completed", 11},
+		{"invalid enum", "priority = urgent", 12},
+		{"invalid bool", "completed = maybe", 13},
+		{"invalid int", "project_id = abc", 14},
+		{"int overflow", "project_id = 99999999999999999999", 14},
+		{"invalid time", "due_date < tomorrow", 12},
+		{"invalid relative time", "due_date < now+7y", 12},
+		{"contains on enum", "priority ~ hi", 10},
+		{"order on bool", "completed < true", 11},
+		{"in on time", "due_date in (now)", 10},
+		{"not in on wrapped field", "tag not in (a)", 9},
+		{"order on wrapped field", "tag != a", 5},
+		{"null on non-nullable field", "created_at is null", 12},
+		{"is without null", "due_date is empty", 13},
+		{"in without list", "priority in high", 13},
+		{"unterminated list", "priority in (high, low", 0},
+		{"list missing comma", "priority in (high low)", 19},
+		{"empty list", "priority in ()", 14},
+		{"operator as value", "title = =", 9},
+		{"too deep", deep, 22},
+		{"too many values", manyValues, 216},
+		{"too long", long, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse(tt.src, testFields)
+			if err == nil {
+				sql, _ := n.SQL()
+				t.Fatalf("Parse(%q) = %q, want error", tt.src, sql)
+			}
+			var fe *Error
+			if !errors.As(err, &fe) {
+				t.Fatalf("Parse(%q) error %v is %T, want *Error", tt.src, err, err)
+			}
+			if fe.Near == "" && tt.pos != 0 || fe.Near != "" && fe.Pos+1 != tt.pos {
+				t.Errorf("Parse(%q): %v, want position %d", tt.src, err, tt.pos)
+			}
+		})
+	}
+}
+
+// FuzzParse checks that no input makes the parser panic and that every
+// failure is a positioned *Error, which handlers turn into a 400.
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{
+		"priority in (high, medium) and due_date < now+7d and not completed",
+		"not (tag = 'a' or tag ~ \"b\\\"\")",
+		"due_date is not null",
+		"((((", "'", "not", "not in", "title in", "priority not", "\\", "é = ü",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		n, err := Parse(src, testFields)
+		if err != nil {
+			var fe *Error
+			if !errors.As(err, &fe) {
+				t.Fatalf("Parse(%q) error %v is %T, want *Error", src, err, err)
+			}
+			return
+		}
+		if n != nil {
+			n.SQL()
+		}
+	})
+}
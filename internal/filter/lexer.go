@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is a lexeme with its byte offset in the source, kept for error
+// messages.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+// isWordRune reports whether r can appear in a bare word. Besides
+// identifiers this covers values such as 2024-05-01T10:00:00+07:00 and
+// now+7d, so they need no quoting.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-:+", r)
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	// offsets maps rune index to byte offset.
+	offsets := make([]int, len(rs)+1)
+	o := 0
+	for i, r := range rs {
+		offsets[i] = o
+		o += len(string(r))
+	}
+	offsets[len(rs)] = o
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := offsets[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", start})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", start})
+			i++
+		case r == ',':
+			toks = append(toks, token{tokComma, ",", start})
+			i++
+		case r == '\'' || r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				b.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, errorAt(start, string(rs[i:]), "unterminated string")
+			}
+			toks = append(toks, token{tokString, b.String(), start})
+			i = j + 1
+		case strings.ContainsRune("=!<>~", r):
+			op := string(r)
+			if i+1 < len(rs) && rs[i+1] == '=' && r != '=' && r != '~' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, errorAt(start, op, "unknown operator")
+			}
+			toks = append(toks, token{tokOp, op, start})
+			i += len(op)
+		case isWordRune(r):
+			j := i
+			for j < len(rs) && isWordRune(rs[j]) {
+				j++
+			}
+			toks = append(toks, token{tokWord, string(rs[i:j]), start})
+			i = j
+		default:
+			return nil, errorAt(start, string(r), "unexpected character")
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
@@ -0,0 +1,316 @@
+package filter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse parses src against fields. An empty expression yields a nil Node.
+// Relative times (now, today) are resolved against the current time.
+func Parse(src string, fields Fields) (Node, error) {
+	return ParseAt(src, fields, time.Now())
+}
+
+// ParseAt is Parse with an explicit current time.
+func ParseAt(src string, fields Fields, now time.Time) (Node, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	if len(src) > MaxLength {
+		return nil, &Error{Msg: "expression too long"}
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, fields: fields, now: now}
+	n, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.unexpected(t, "expected and, or or end of expression")
+	}
+	return n, nil
+}
+
+type parser struct {
+	toks   []token
+	i      int
+	fields Fields
+	now    time.Time
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) unexpected(t token, msg string) *Error {
+	if t.kind == tokEOF {
+		return &Error{Pos: t.pos, Msg: msg}
+	}
+	return errorAt(t.pos, t.text, msg)
+}
+
+func (p *parser) expr(depth int) (Node, error) {
+	if depth > MaxDepth {
+		return nil, p.unexpected(p.peek(), "expression nested too deeply")
+	}
+	l, err := p.term(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("or") {
+		p.next()
+		r, err := p.term(depth)
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: "OR", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) term(depth int) (Node, error) {
+	l, err := p.factor(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("and") {
+		p.next()
+		r, err := p.factor(depth)
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: "AND", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) factor(depth int) (Node, error) {
+	t := p.peek()
+	switch {
+	case t.is("not"):
+		p.next()
+		if depth+1 > MaxDepth {
+			return nil, p.unexpected(t, "expression nested too deeply")
+		}
+		x, err := p.factor(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return not{x}, nil
+	case t.kind == tokLParen:
+		p.next()
+		x, err := p.expr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokRParen {
+			return nil, p.unexpected(c, "expected )")
+		}
+		return x, nil
+	case t.kind == tokWord:
+		return p.predicate()
+	}
+	return nil, p.unexpected(t, "expected field name, not or (")
+}
+
+var sqlOps = map[string]string{
+	"=":  "=",
+	"!=": "IS DISTINCT FROM",
+	"<":  "<",
+	"<=": "<=",
+	">":  ">",
+	">=": ">=",
+	"~":  "ILIKE",
+}
+
+func (p *parser) predicate() (Node, error) {
+	name := p.next()
+	f, ok := p.fields[strings.ToLower(name.text)]
+	if !ok {
+		return nil, errorAt(name.pos, name.text, "unknown field %q", name.text)
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokOp:
+		p.next()
+		if !opAllowed(f, t.text) {
+			return nil, errorAt(t.pos, t.text, "operator %s not supported for %s", t.text, name.text)
+		}
+		vt := p.next()
+		v, err := p.value(f, vt)
+		if err != nil {
+			return nil, err
+		}
+		if t.text == "~" {
+			v = likePattern(v.(string))
+		}
+		return predicate{field: f, op: sqlOps[t.text], values: []interface{}{v}}, nil
+
+	case t.is("in"), t.is("not") && p.toks[p.i+1].is("in"):
+		op := "IN"
+		if t.is("not") {
+			op = "NOT IN"
+			p.next()
+		}
+		in := p.next()
+		if f.Kind == Bool || f.Kind == Time || (f.Wrap != "" && op == "NOT IN") {
+			return nil, errorAt(in.pos, in.text, "operator %s not supported for %s", strings.ToLower(op), name.text)
+		}
+		values, err := p.list(f)
+		if err != nil {
+			return nil, err
+		}
+		return predicate{field: f, op: op, values: values}, nil
+
+	case t.is("is"):
+		p.next()
+		op := "IS NULL"
+		if p.peek().is("not") {
+			p.next()
+			op = "IS NOT NULL"
+		}
+		nt := p.next()
+		if !nt.is("null") {
+			return nil, p.unexpected(nt, "expected null")
+		}
+		if !f.Nullable {
+			return nil, errorAt(t.pos, t.text, "%s cannot be null", name.text)
+		}
+		return predicate{field: f, op: op}, nil
+	}
+
+	if f.Kind == Bool {
+		return predicate{field: f, op: "=", values: []interface{}{true}}, nil
+	}
+	return nil, p.unexpected(t, "expected operator after "+name.text)
+}
+
+func opAllowed(f Field, op string) bool {
+	if f.Wrap != "" && op != "=" && op != "~" {
+		return false
+	}
+	switch f.Kind {
+	case Text:
+		return op == "=" || op == "!=" || op == "~"
+	case Bool, Enum:
+		return op == "=" || op == "!="
+	case Int, Time:
+		return op != "~"
+	}
+	return false
+}
+
+func (p *parser) list(f Field) ([]interface{}, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, p.unexpected(t, "expected (")
+	}
+	var values []interface{}
+	for {
+		vt := p.next()
+		v, err := p.value(f, vt)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if len(values) > MaxValues {
+			return nil, errorAt(vt.pos, vt.text, "too many values")
+		}
+		switch t := p.next(); t.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, p.unexpected(t, "expected , or )")
+		}
+	}
+}
+
+func (p *parser) value(f Field, t token) (interface{}, error) {
+	if t.kind != tokWord && t.kind != tokString {
+		return nil, p.unexpected(t, "expected "+f.Kind.String()+" value")
+	}
+	bad := func() (interface{}, error) {
+		return nil, errorAt(t.pos, t.text, "invalid %s value", f.Kind)
+	}
+	switch f.Kind {
+	case Text:
+		return t.text, nil
+	case Bool:
+		b, err := strconv.ParseBool(t.text)
+		if err != nil {
+			return bad()
+		}
+		return b, nil
+	case Enum:
+		for _, v := range f.Values {
+			if strings.EqualFold(v, t.text) {
+				return v, nil
+			}
+		}
+		return nil, errorAt(t.pos, t.text, "invalid value, expected one of %s", strings.Join(f.Values, ", "))
+	case Int:
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return bad()
+		}
+		return n, nil
+	case Time:
+		v, ok := parseTime(t.text, p.now)
+		if !ok {
+			return bad()
+		}
+		return v, nil
+	}
+	return bad()
+}
+
+var relativeTime = regexp.MustCompile(`^(?i)(now|today)(?:([+-])(\d{1,6})([mhdw]))?$`)
+
+// parseTime accepts dates, RFC 3339 timestamps and now/today with an
+// optional offset. today is midnight of now's day in now's location.
+func parseTime(s string, now time.Time) (time.Time, bool) {
+	if m := relativeTime.FindStringSubmatch(s); m != nil {
+		t := now
+		if strings.EqualFold(m[1], "today") {
+			y, mo, d := now.Date()
+			t = time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+		}
+		if m[2] == "" {
+			return t, true
+		}
+		n, _ := strconv.Atoi(m[3])
+		if m[2] == "-" {
+			n = -n
+		}
+		switch strings.ToLower(m[4]) {
+		case "m":
+			return t.Add(time.Duration(n) * time.Minute), true
+		case "h":
+			return t.Add(time.Duration(n) * time.Hour), true
+		case "d":
+			return t.AddDate(0, 0, n), true
+		default:
+			return t.AddDate(0, 0, 7*n), true
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
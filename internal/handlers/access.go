@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+)
+
+func actorOf(c *fiber.Ctx) service.Actor {
+	uid, _ := middleware.GetUserID(c)
+	return service.Actor{ID: uid, Role: models.Role(middleware.GetUserRole(c))}
+}
+
+// errorStatus maps access, lookup and concurrency failures to
+// 403/404/412/409 and everything else to fallback.
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return fiber.StatusPreconditionFailed
+	case errors.Is(err, repository.ErrVersionConflict):
+		return fiber.StatusConflict
+	}
+	return fallback
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type AttachmentHandler struct {
+	svc service.AttachmentService
+}
+
+func NewAttachmentHandler(s service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{svc: s}
+}
+
+// @Summary List attachments of a todo
+// @Security Bearer
+// @Tags Attachments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	items, err := h.svc.List(todoID, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusNotFound), err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Upload attachment
+// @Security Bearer
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param file formData file true "File"
+// @Success 201 {object} map[string]interface{}
+// @Router /todos/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "file required")
+	}
+	src, err := fileHeader.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	defer src.Close()
+
+	a, err := h.svc.Upload(todoID, fileHeader.Filename, fileHeader.Size, src, actorOf(c))
+	if err != nil {
+		return response.Error(c, uploadStatus(err), err.Error())
+	}
+	return response.Created(c, a)
+}
+
+// @Summary Download attachment
+// @Security Bearer
+// @Tags Attachments
+// @Produce octet-stream
+// @Param id path int true "Todo ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 200 {file} file
+// @Router /todos/{id}/attachments/{attachmentId}/download [get]
+func (h *AttachmentHandler) Download(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	attID, err := paramID(c, "attachmentId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid attachment id")
+	}
+	a, rc, err := h.svc.Open(todoID, attID, actorOf(c))
+	if err != nil {
+		status := errorStatus(err, fiber.StatusInternalServerError)
+		if errors.Is(err, storage.ErrNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return response.Error(c, status, err.Error())
+	}
+	c.Set(fiber.HeaderContentType, a.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	// fasthttp closes the stream once the body has been written.
+	return c.SendStream(rc, int(a.Size))
+}
+
+// @Summary Delete attachment
+// @Security Bearer
+// @Tags Attachments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 204 {string} string "No Content"
+// @Router /todos/{id}/attachments/{attachmentId} [delete]
+func (h *AttachmentHandler) Delete(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	attID, err := paramID(c, "attachmentId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid attachment id")
+	}
+	if err := h.svc.Delete(todoID, attID, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
+
+func uploadStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrFileTooLarge):
+		return fiber.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrUnsupportedMedia):
+		return fiber.StatusUnsupportedMediaType
+	case errors.Is(err, service.ErrQuotaExceeded):
+		return fiber.StatusConflict
+	}
+	return errorStatus(err, fiber.StatusBadRequest)
+}
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/caldav"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/ical"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+)
+
+// DAVPrefix is where the CalDAV tree is mounted.
+const DAVPrefix = "/dav"
+
+// DAVMethods are the WebDAV methods Fiber must accept besides the
+// standard ones.
+var DAVMethods = []string{"PROPFIND", "REPORT"}
+
+// CalDAVHandler serves a minimal CalDAV tree for the authenticated user:
+//
+//	/dav/                               service root
+//	/dav/principals/{id}/               the user's principal
+//	/dav/calendars/{id}/                calendar home
+//	/dav/calendars/{id}/todos/          task collection (VTODO)
+//	/dav/calendars/{id}/todos/{uid}.ics one todo
+type CalDAVHandler struct {
+	svc service.CalDAVService
+	us  service.UserService
+}
+
+func NewCalDAVHandler(s service.CalDAVService, us service.UserService) *CalDAVHandler {
+	return &CalDAVHandler{svc: s, us: us}
+}
+
+type davNode int
+
+const (
+	davRoot davNode = iota
+	davPrincipal
+	davHome
+	davCollection
+	davResource
+)
+
+var (
+	propResourceType = xml.Name{Space: caldav.NSDAV, Local: "resourcetype"}
+	propCalendarData = xml.Name{Space: caldav.NSCalDAV, Local: "calendar-data"}
+)
+
+// WellKnown redirects /.well-known/caldav to the service root (RFC 6764).
+func (h *CalDAVHandler) WellKnown(c *fiber.Ctx) error {
+	return c.Redirect(DAVPrefix+"/", fiber.StatusMovedPermanently)
+}
+
+func (h *CalDAVHandler) Serve(c *fiber.Ctx) error {
+	actor := actorOf(c)
+	node, name, ok := h.resolve(c.Params("*"), actor.ID)
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	c.Set("DAV", "1, 3, calendar-access")
+
+	switch c.Method() {
+	case fiber.MethodOptions:
+		c.Set(fiber.HeaderAllow, "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		return c.SendStatus(fiber.StatusOK)
+	case "PROPFIND":
+		return h.propfind(c, node, name, actor)
+	case "REPORT":
+		if node != davCollection {
+			return c.SendStatus(fiber.StatusMethodNotAllowed)
+		}
+		return h.report(c, actor)
+	case fiber.MethodGet, fiber.MethodHead:
+		if node != davResource {
+			return c.SendStatus(fiber.StatusMethodNotAllowed)
+		}
+		return h.get(c, name, actor)
+	case fiber.MethodPut:
+		if node != davResource {
+			return c.SendStatus(fiber.StatusMethodNotAllowed)
+		}
+		return h.put(c, name, actor)
+	case fiber.MethodDelete:
+		if node != davResource {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		err := h.svc.Delete(name, c.Get(fiber.HeaderIfMatch), actor)
+		if err != nil {
+			return c.SendStatus(errorStatus(err, fiber.StatusInternalServerError))
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	return c.SendStatus(fiber.StatusMethodNotAllowed)
+}
+
+// resolve maps a path below DAVPrefix to a node. Only the actor's own
+// principal and calendars exist.
+func (h *CalDAVHandler) resolve(p string, userID uint) (davNode, string, bool) {
+	p, err := url.PathUnescape(p)
+	if err != nil {
+		return 0, "", false
+	}
+	parts := strings.FieldsFunc(p, func(r rune) bool { return r == '/' })
+	if len(parts) == 0 {
+		return davRoot, "", true
+	}
+	if len(parts) < 2 || parts[1] != strconv.FormatUint(uint64(userID), 10) {
+		return 0, "", false
+	}
+	switch {
+	case parts[0] == "principals" && len(parts) == 2:
+		return davPrincipal, "", true
+	case parts[0] != "calendars":
+		return 0, "", false
+	case len(parts) == 2:
+		return davHome, "", true
+	case parts[2] != "todos":
+		return 0, "", false
+	case len(parts) == 3:
+		return davCollection, "", true
+	case len(parts) == 4 && strings.HasSuffix(parts[3], ".ics") && len(parts[3]) > len(".ics"):
+		return davResource, strings.TrimSuffix(parts[3], ".ics"), true
+	}
+	return 0, "", false
+}
+
+func principalHref(id uint) string { return fmt.Sprintf("%s/principals/%d/", DAVPrefix, id) }
+func homeHref(id uint) string      { return fmt.Sprintf("%s/calendars/%d/", DAVPrefix, id) }
+func collectionHref(id uint) string {
+	return homeHref(id) + "todos/"
+}
+func resourceHref(id uint, t *models.Todo) string {
+	return collectionHref(id) + url.PathEscape(todoio.UID(t)) + ".ics"
+}
+
+func (h *CalDAVHandler) propfind(c *fiber.Ctx, node davNode, name string, actor service.Actor) error {
+	req, err := caldav.Parse(bytes.NewReader(c.Body()))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+	depth := c.Get("Depth", "1")
+	ms := &caldav.Multistatus{}
+	switch node {
+	case davRoot, davPrincipal:
+		props, err := h.principalProps(actor, node == davPrincipal)
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		ms.Responses = append(ms.Responses, caldav.NewResponse(req, c.Path(), props))
+	case davHome:
+		ms.Responses = append(ms.Responses, caldav.NewResponse(req, homeHref(actor.ID), []caldav.Property{
+			caldav.Prop("resourcetype", caldav.Prop("collection")),
+			caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "displayname"}, "Calendars"),
+			caldav.Prop("current-user-principal", caldav.Href(principalHref(actor.ID))),
+		}))
+		if depth != "0" {
+			token, err := h.svc.SyncToken(actor)
+			if err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			ms.Responses = append(ms.Responses, caldav.NewResponse(req, collectionHref(actor.ID), collectionProps(actor, token)))
+		}
+	case davCollection:
+		if depth == "0" {
+			token, err := h.svc.SyncToken(actor)
+			if err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			ms.Responses = append(ms.Responses, caldav.NewResponse(req, collectionHref(actor.ID), collectionProps(actor, token)))
+			break
+		}
+		todos, token, err := h.svc.List(actor)
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		ms.Responses = append(ms.Responses, caldav.NewResponse(req, collectionHref(actor.ID), collectionProps(actor, token)))
+		for i := range todos {
+			resp, err := resourceResponse(req, actor.ID, &todos[i])
+			if err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			ms.Responses = append(ms.Responses, resp)
+		}
+	case davResource:
+		t, err := h.svc.Get(name, actor)
+		if err != nil {
+			return c.SendStatus(errorStatus(err, fiber.StatusInternalServerError))
+		}
+		resp, err := resourceResponse(req, actor.ID, t)
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		ms.Responses = append(ms.Responses, resp)
+	}
+	return sendMultistatus(c, ms)
+}
+
+func (h *CalDAVHandler) principalProps(actor service.Actor, principal bool) ([]caldav.Property, error) {
+	u, err := h.us.GetByID(actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	resourceType := caldav.Prop("resourcetype", caldav.Prop("collection"))
+	if principal {
+		resourceType = caldav.Prop("resourcetype", caldav.Prop("principal"))
+	}
+	return []caldav.Property{
+		resourceType,
+		caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "displayname"}, u.Name),
+		caldav.Prop("current-user-principal", caldav.Href(principalHref(actor.ID))),
+		caldav.Prop("principal-URL", caldav.Href(principalHref(actor.ID))),
+		caldav.CalProp("calendar-home-set", caldav.Href(homeHref(actor.ID))),
+		caldav.CalProp("calendar-user-address-set", caldav.Href("mailto:"+u.Email)),
+	}, nil
+}
+
+func collectionProps(actor service.Actor, token string) []caldav.Property {
+	privilege := func(name string) caldav.Property {
+		return caldav.Prop("privilege", caldav.Prop(name))
+	}
+	report := func(name caldav.Property) caldav.Property {
+		return caldav.Prop("supported-report", caldav.Prop("report", name))
+	}
+	return []caldav.Property{
+		caldav.Prop("resourcetype", caldav.Prop("collection"), caldav.CalProp("calendar")),
+		caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "displayname"}, "Todos"),
+		caldav.CalProp("supported-calendar-component-set", caldav.Property{
+			XMLName: xml.Name{Space: caldav.NSCalDAV, Local: "comp"},
+			Attrs:   []xml.Attr{{Name: xml.Name{Local: "name"}, Value: "VTODO"}},
+		}),
+		caldav.Prop("current-user-principal", caldav.Href(principalHref(actor.ID))),
+		caldav.Prop("current-user-privilege-set", privilege("read"), privilege("write"), privilege("write-content"), privilege("bind"), privilege("unbind")),
+		caldav.Prop("supported-report-set",
+			report(caldav.CalProp("calendar-query")),
+			report(caldav.CalProp("calendar-multiget")),
+			report(caldav.Prop("sync-collection"))),
+		caldav.Text(xml.Name{Space: caldav.NSCS, Local: "getctag"}, token),
+		caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "sync-token"}, token),
+	}
+}
+
+// resourceResponse describes one todo; calendar-data is only rendered
+// when asked for.
+func resourceResponse(req *caldav.Request, userID uint, t *models.Todo) (caldav.Response, error) {
+	props := []caldav.Property{
+		caldav.Prop("resourcetype"),
+		caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "getetag"}, service.DavETag(t)),
+		caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "getcontenttype"}, "text/calendar; charset=utf-8; component=VTODO"),
+		caldav.Text(xml.Name{Space: caldav.NSDAV, Local: "getlastmodified"}, t.UpdatedAt.UTC().Format(http.TimeFormat)),
+	}
+	if !req.AllProp && req.Wants(propCalendarData) {
+		data, err := service.CalendarData(t)
+		if err != nil {
+			return caldav.Response{}, err
+		}
+		props = append(props, caldav.Text(propCalendarData, string(data)))
+	}
+	return caldav.NewResponse(req, resourceHref(userID, t), props), nil
+}
+
+func (h *CalDAVHandler) report(c *fiber.Ctx, actor service.Actor) error {
+	req, err := caldav.Parse(bytes.NewReader(c.Body()))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+	ms := &caldav.Multistatus{}
+	switch req.Root {
+	case caldav.CalendarQuery:
+		var todos []models.Todo
+		if wantsVTodo(req.Components) {
+			if todos, _, err = h.svc.List(actor); err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		for i := range todos {
+			resp, err := resourceResponse(req, actor.ID, &todos[i])
+			if err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			ms.Responses = append(ms.Responses, resp)
+		}
+	case caldav.CalendarMultiget:
+		for _, href := range req.Hrefs {
+			node, name, ok := h.resolve(strings.TrimPrefix(hrefPath(href), DAVPrefix), actor.ID)
+			if !ok || node != davResource {
+				ms.Responses = append(ms.Responses, caldav.StatusResponse(href, http.StatusNotFound))
+				continue
+			}
+			t, err := h.svc.Get(name, actor)
+			if err != nil {
+				ms.Responses = append(ms.Responses, caldav.StatusResponse(href, errorStatus(err, http.StatusInternalServerError)))
+				continue
+			}
+			resp, err := resourceResponse(req, actor.ID, t)
+			if err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			ms.Responses = append(ms.Responses, resp)
+		}
+	case caldav.SyncCollection:
+		todos, token, err := h.svc.Changes(req.SyncToken, actor)
+		if errors.Is(err, service.ErrInvalidSyncToken) {
+			c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+			return c.Status(fiber.StatusForbidden).Send(caldav.Error(xml.Name{Space: caldav.NSDAV, Local: "valid-sync-token"}))
+		}
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		for i := range todos {
+			resp, err := resourceResponse(req, actor.ID, &todos[i])
+			if err != nil {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+			ms.Responses = append(ms.Responses, resp)
+		}
+		ms.SyncToken = token
+	default:
+		c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+		return c.Status(fiber.StatusForbidden).Send(caldav.Error(xml.Name{Space: caldav.NSDAV, Local: "supported-report"}))
+	}
+	return sendMultistatus(c, ms)
+}
+
+// wantsVTodo reports whether a calendar-query's comp-filters can match
+// VTODOs; other filters (time ranges, properties) are not applied.
+func wantsVTodo(components []string) bool {
+	for _, comp := range components {
+		if comp != "VCALENDAR" && comp != "VTODO" {
+			return false
+		}
+	}
+	return true
+}
+
+// hrefPath strips scheme and host from an absolute href.
+func hrefPath(href string) string {
+	if u, err := url.Parse(href); err == nil {
+		return path.Clean(u.EscapedPath())
+	}
+	return href
+}
+
+func (h *CalDAVHandler) get(c *fiber.Ctx, name string, actor service.Actor) error {
+	t, err := h.svc.Get(name, actor)
+	if err != nil {
+		return c.SendStatus(errorStatus(err, fiber.StatusInternalServerError))
+	}
+	etag := service.DavETag(t)
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, t.UpdatedAt.UTC().Format(http.TimeFormat))
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	data, err := service.CalendarData(t)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Send(data)
+}
+
+func (h *CalDAVHandler) put(c *fiber.Ctx, name string, actor service.Actor) error {
+	t, created, err := h.svc.Put(name, c.Body(), c.Get(fiber.HeaderIfMatch), c.Get(fiber.HeaderIfNoneMatch), actor)
+	switch {
+	case errors.Is(err, service.ErrUnsupportedComponent):
+		c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+		return c.Status(fiber.StatusForbidden).Send(caldav.Error(xml.Name{Space: caldav.NSCalDAV, Local: "supported-calendar-component"}))
+	case errors.Is(err, ical.ErrMalformed):
+		c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+		return c.Status(fiber.StatusBadRequest).Send(caldav.Error(xml.Name{Space: caldav.NSCalDAV, Local: "valid-calendar-data"}))
+	case err != nil:
+		return c.Status(errorStatus(err, fiber.StatusBadRequest)).SendString(err.Error())
+	}
+	c.Set(fiber.HeaderETag, service.DavETag(t))
+	if created {
+		return c.SendStatus(fiber.StatusCreated)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DAVAuth wraps middleware.BasicAuth for the CalDAV tree.
+func DAVAuth(auth service.AuthService) fiber.Handler {
+	return middleware.BasicAuth("Todos CalDAV", auth.Authenticate)
+}
+
+func sendMultistatus(c *fiber.Ctx, ms *caldav.Multistatus) error {
+	body, err := ms.Marshal()
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Status(fiber.StatusMultiStatus).Send(body)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type CommentHandler struct {
+	svc service.CommentService
+}
+
+func NewCommentHandler(s service.CommentService) *CommentHandler {
+	return &CommentHandler{svc: s}
+}
+
+type commentBody struct {
+	Body string `json:"body"` // Markdown
+}
+
+// @Summary List comments of a todo
+// @Security Bearer
+// @Tags Comments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/comments [get]
+func (h *CommentHandler) List(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	comments, err := h.svc.List(todoID, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, comments)
+}
+
+// @Summary Add comment
+// @Security Bearer
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param payload body map[string]interface{} true "Comment body (Markdown, @email mentions)"
+// @Success 201 {object} map[string]interface{}
+// @Router /todos/{id}/comments [post]
+func (h *CommentHandler) Create(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var body commentBody
+	if err := c.BodyParser(&body); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	comment, err := h.svc.Create(todoID, body.Body, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.Created(c, comment)
+}
+
+// @Summary Edit comment (author only)
+// @Security Bearer
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param commentId path int true "Comment ID"
+// @Param payload body map[string]interface{} true "Comment body"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/comments/{commentId} [put]
+func (h *CommentHandler) Update(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	commentID, err := paramID(c, "commentId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid comment id")
+	}
+	var body commentBody
+	if err := c.BodyParser(&body); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	comment, err := h.svc.Update(todoID, commentID, body.Body, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, comment)
+}
+
+// @Summary Delete comment (author or admin)
+// @Security Bearer
+// @Tags Comments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param commentId path int true "Comment ID"
+// @Success 204 {string} string "No Content"
+// @Router /todos/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) Delete(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	commentID, err := paramID(c, "commentId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid comment id")
+	}
+	if err := h.svc.Delete(todoID, commentID, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
+
+// @Summary Activity feed (comments and field changes, newest first)
+// @Security Bearer
+// @Tags Comments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param limit query int false "limit (default 50)"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/activity [get]
+func (h *CommentHandler) Activity(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	entries, err := h.svc.Activity(todoID, limit, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, entries)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setVersionETag exposes a todo version as a strong ETag such as "3".
+func setVersionETag(c *fiber.Ctx, version uint) {
+	c.Set(fiber.HeaderETag, `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+}
+
+// ifMatchVersion reads the If-Match header. It returns nil when there is no
+// precondition (header absent or "*"). A header that does not name a
+// version yields 0, which never matches.
+func ifMatchVersion(c *fiber.Ctx) *uint {
+	h := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if h == "" || h == "*" {
+		return nil
+	}
+	var v uint
+	// Weak validators never match under If-Match.
+	if tag, ok := strings.CutPrefix(h, `"`); ok {
+		if n, err := strconv.ParseUint(strings.TrimSuffix(tag, `"`), 10, 64); err == nil && strings.HasSuffix(tag, `"`) {
+			v = uint(n)
+		}
+	}
+	return &v
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/realtime"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+const (
+	// sseKeepAlive is how often an idle stream sends a comment so proxies
+	// keep the connection open.
+	sseKeepAlive = 25 * time.Second
+	// sseWriteTimeout bounds each write; the server's WriteTimeout would
+	// otherwise end the stream.
+	sseWriteTimeout = 10 * time.Second
+	// sseRetry tells EventSource how long to wait before reconnecting.
+	sseRetry = 3000
+	// ssePendingPoll is how soon to look again for events held back until
+	// an older transaction finishes.
+	ssePendingPoll = time.Second
+)
+
+type EventHandler struct {
+	svc service.EventService
+	hub *realtime.Hub
+}
+
+func NewEventHandler(s service.EventService, hub *realtime.Hub) *EventHandler {
+	return &EventHandler{svc: s, hub: hub}
+}
+
+// @Summary Stream my todo changes (Server-Sent Events)
+// @Description Pushes todo.created, todo.updated, todo.completed and todo.deleted events as they happen on any app instance. Each event's id can be sent back as the Last-Event-ID header (EventSource does this on reconnect) or last_event_id query to resume. Events are sent in commit-safe order: one is held back while a transaction that may still record an earlier event is open, so a resumed stream misses nothing. The token may also be passed as access_token.
+// @Security Bearer
+// @Tags Events
+// @Produce text/event-stream
+// @Param last_event_id query int false "resume after this event"
+// @Param access_token query string false "JWT, for clients that cannot set headers"
+// @Success 200 {string} string "event stream"
+// @Router /events [get]
+func (h *EventHandler) Stream(c *fiber.Ctx) error {
+	actor := actorOf(c)
+	resume := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	var last uint
+	if resume != "" {
+		id, err := strconv.ParseUint(resume, 10, 64)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid Last-Event-ID")
+		}
+		last = uint(id)
+	} else {
+		id, err := h.svc.Latest()
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		}
+		last = id
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	conn := c.Context().Conn()
+	sub := h.hub.Subscribe(actor.ID)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.hub.Unsubscribe(sub)
+		flush := func() error {
+			_ = conn.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+			return w.Flush()
+		}
+		keepAlive := time.NewTicker(sseKeepAlive)
+		defer keepAlive.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+		for {
+			events, pending, err := h.svc.After(last, actor)
+			if err != nil {
+				log.Printf("events: %v", err)
+				return
+			}
+			for _, e := range events {
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Payload)
+				last = e.ID
+			}
+			if err := flush(); err != nil {
+				return
+			}
+			if len(events) == service.MaxEventBatch {
+				continue
+			}
+			var poll <-chan time.Time
+			if pending {
+				poll = time.After(ssePendingPoll)
+			}
+			select {
+			case <-poll:
+			case <-sub.Wake():
+			case <-sub.Done():
+				return
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				if err := flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type FeedHandler struct {
+	svc service.FeedService
+}
+
+func NewFeedHandler(s service.FeedService) *FeedHandler {
+	return &FeedHandler{svc: s}
+}
+
+// @Summary Create or regenerate my calendar feed URL
+// @Description Returns a secret subscription URL; any previous URL stops working. The URL is only shown once.
+// @Security Bearer
+// @Tags Profile
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /me/feed [post]
+func (h *FeedHandler) Rotate(c *fiber.Ctx) error {
+	uid, _ := middleware.GetUserID(c)
+	token, err := h.svc.Rotate(uid)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.OK(c, fiber.Map{"url": c.BaseURL() + "/feeds/" + token + "/todos.ics"})
+}
+
+// @Summary Revoke my calendar feed URL
+// @Security Bearer
+// @Tags Profile
+// @Success 204 {string} string "No Content"
+// @Router /me/feed [delete]
+func (h *FeedHandler) Revoke(c *fiber.Ctx) error {
+	uid, _ := middleware.GetUserID(c)
+	if err := h.svc.Revoke(uid); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.NoContent(c)
+}
+
+// @Summary Calendar feed of todos with a due date
+// @Description Public iCalendar feed authenticated by the secret token in the path. Supports If-None-Match.
+// @Tags Feeds
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Param kind query string false "all|todo|event (default all)"
+// @Success 200 {string} string "iCalendar"
+// @Success 304 {string} string "Not Modified"
+// @Router /feeds/{token}/todos.ics [get]
+func (h *FeedHandler) Serve(c *fiber.Ctx) error {
+	kind := service.FeedKind(c.Query("kind", string(service.FeedAll)))
+	switch kind {
+	case service.FeedAll, service.FeedTodos, service.FeedEvents:
+	default:
+		return response.Error(c, fiber.StatusBadRequest, "kind must be all, todo or event")
+	}
+	feed, err := h.svc.Calendar(c.Params("token"), kind)
+	if err != nil {
+		if status := errorStatus(err, fiber.StatusInternalServerError); status != fiber.StatusNotFound {
+			return response.Error(c, status, err.Error())
+		}
+		return response.Error(c, fiber.StatusNotFound, "not found")
+	}
+	c.Set(fiber.HeaderETag, feed.ETag)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), feed.ETag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="todos.ics"`)
+	return c.Send(feed.Body)
+}
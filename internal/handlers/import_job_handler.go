@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/importer"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type ImportJobHandler struct {
+	svc service.ImportJobService
+}
+
+func NewImportJobHandler(s service.ImportJobService) *ImportJobHandler {
+	return &ImportJobHandler{svc: s}
+}
+
+// @Summary Import an export of Todoist, Trello or Microsoft To Do
+// @Description Starts a background job importing the file sent as the body or as multipart field "file". Projects, boards and lists become projects, labels become tags, sub-tasks and checklists become checklist items. Poll the job for its report, which lists skipped data. Re-importing updates the todos created before.
+// @Security Bearer
+// @Tags Imports
+// @Accept json,text/csv,multipart/form-data
+// @Produce json
+// @Param source query string true "todoist|trello|mstodo"
+// @Param dry_run query bool false "validate only"
+// @Success 202 {object} map[string]interface{}
+// @Router /imports [post]
+func (h *ImportJobHandler) Create(c *fiber.Ctx) error {
+	source, err := importer.ParseSource(c.Query("source"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	body, _, name, err := uploadedFile(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	job, err := h.svc.Start(source, name, data, c.QueryBool("dry_run", false), actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	c.Location(fmt.Sprintf("/api/v1/imports/%d", job.ID))
+	return response.Accepted(c, job)
+}
+
+// @Summary List my recent import jobs
+// @Security Bearer
+// @Tags Imports
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /imports [get]
+func (h *ImportJobHandler) List(c *fiber.Ctx) error {
+	jobs, err := h.svc.List(actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.OK(c, jobs)
+}
+
+// @Summary Get an import job and its report
+// @Security Bearer
+// @Tags Imports
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /imports/{id} [get]
+func (h *ImportJobHandler) Get(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	job, err := h.svc.Get(id, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.OK(c, job)
+}
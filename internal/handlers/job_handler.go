@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type JobHandler struct {
+	svc service.JobService
+}
+
+func NewJobHandler(s service.JobService) *JobHandler {
+	return &JobHandler{svc: s}
+}
+
+// @Summary List background jobs (admin)
+// @Description Newest first, with the number of jobs per status.
+// @Security Bearer
+// @Tags Admin
+// @Produce json
+// @Param status query string false "queued|running|succeeded|failed"
+// @Param type query string false "job type, e.g. import.run"
+// @Param before query int false "only jobs with a lower ID (paging)"
+// @Param limit query int false "max 100"
+// @Success 200 {object} service.JobList
+// @Router /admin/jobs [get]
+func (h *JobHandler) List(c *fiber.Ctx) error {
+	before := c.QueryInt("before", 0)
+	if before < 0 {
+		before = 0
+	}
+	list, err := h.svc.List(models.JobStatus(c.Query("status")), c.Query("type"), uint(before), c.QueryInt("limit", 50))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.OK(c, list)
+}
+
+// @Summary Get a background job (admin)
+// @Security Bearer
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} models.Job
+// @Router /admin/jobs/{id} [get]
+func (h *JobHandler) Get(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	job, err := h.svc.Get(id)
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.OK(c, job)
+}
+
+// @Summary Retry a failed background job (admin)
+// @Security Bearer
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 202 {object} models.Job
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/jobs/{id}/retry [post]
+func (h *JobHandler) Retry(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	job, err := h.svc.Retry(id)
+	if errors.Is(err, service.ErrJobNotFailed) {
+		return response.Error(c, fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.Accepted(c, job)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type NotificationHandler struct {
+	svc service.NotificationService
+}
+
+func NewNotificationHandler(s service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: s}
+}
+
+// @Summary List notifications
+// @Security Bearer
+// @Tags Notifications
+// @Produce json
+// @Param unread query bool false "only unread (default true)"
+// @Param limit query int false "limit"
+// @Param page query int false "page"
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	unread := c.Query("unread", "true")
+	unreadOnly := unread == "true" || unread == "1"
+
+	uid, _ := middleware.GetUserID(c)
+	items, total, err := h.svc.List(uid, unreadOnly, limit, page)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.List(c, items, response.Meta{Limit: limit, Page: page, Total: total})
+}
+
+// @Summary Mark notification as read
+// @Security Bearer
+// @Tags Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	uid, _ := middleware.GetUserID(c)
+	n, err := h.svc.MarkRead(uid, id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.OK(c, n)
+}
+
+// @Summary Mark all notifications as read
+// @Security Bearer
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	uid, _ := middleware.GetUserID(c)
+	n, err := h.svc.MarkAllRead(uid)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.OK(c, fiber.Map{"updated": n})
+}
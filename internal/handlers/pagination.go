@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+// pageRequest reads the limit, page, after/before cursors and the count
+// flag, and returns the request with the page number it stands for. The
+// limit is clamped before the offset is derived from it, so pages never
+// overlap or skip rows. The total is computed by default for offset pages
+// only, since counting defeats the point of seeking by cursor.
+func pageRequest(c *fiber.Ctx) (repository.PageRequest, int, error) {
+	limit := service.PageSize(c.QueryInt("limit", service.DefaultPageSize))
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	req := repository.PageRequest{Limit: limit, Offset: (page - 1) * limit}
+	if v := c.Query("after"); v != "" {
+		cur, err := repository.DecodeCursor(v)
+		if err != nil {
+			return req, page, errors.New("invalid after cursor")
+		}
+		req.After = cur
+	}
+	if v := c.Query("before"); v != "" {
+		cur, err := repository.DecodeCursor(v)
+		if err != nil {
+			return req, page, errors.New("invalid before cursor")
+		}
+		req.Before = cur
+	}
+	if req.After != nil && req.Before != nil {
+		return req, page, errors.New("use either after or before, not both")
+	}
+	if req.After != nil || req.Before != nil {
+		req.Offset = 0
+	}
+	req.Count = c.QueryBool("count", req.After == nil && req.Before == nil)
+	return req, page, nil
+}
+
+// todoPage writes one page of todos with its pagination meta and links.
+func todoPage(c *fiber.Ctx, result *repository.TodoPage, req repository.PageRequest, page int) error {
+	meta := response.Meta{Limit: req.Limit}
+	if req.After == nil && req.Before == nil {
+		meta.Page = page
+	}
+	if result.Total != nil {
+		meta.Total = *result.Total
+	}
+	if result.Next != nil {
+		meta.NextCursor = result.Next.Encode()
+	}
+	if result.Prev != nil {
+		meta.PrevCursor = result.Prev.Encode()
+	}
+	setPageLinks(c, meta)
+	return response.List(c, result.Todos, meta)
+}
+
+// setPageLinks emits an RFC 8288 Link header with first/next/prev links
+// that keep the request's other query parameters.
+func setPageLinks(c *fiber.Ctx, meta response.Meta) {
+	base := c.BaseURL() + c.Path()
+	link := func(rel, param, cursor string) string {
+		q := url.Values{}
+		c.Context().QueryArgs().VisitAll(func(k, v []byte) {
+			q.Add(string(k), string(v))
+		})
+		q.Del("after")
+		q.Del("before")
+		q.Del("page")
+		if param != "" {
+			q.Set(param, cursor)
+		}
+		u := base
+		if enc := q.Encode(); enc != "" {
+			u += "?" + enc
+		}
+		return fmt.Sprintf(`<%s>; rel="%s"`, u, rel)
+	}
+	links := []string{link("first", "", "")}
+	if meta.NextCursor != "" {
+		links = append(links, link("next", "after", meta.NextCursor))
+	}
+	if meta.PrevCursor != "" {
+		links = append(links, link("prev", "before", meta.PrevCursor))
+	}
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The offset must come from the clamped limit, or pages overlap (limit=0)
+// or skip rows (limit above the maximum).
+func TestPageRequestClampsBeforeOffset(t *testing.T) {
+	tests := []struct {
+		query         string
+		limit, offset int
+		page          int
+	}{
+		{"", 10, 0, 1},
+		{"?page=3", 10, 20, 3},
+		{"?limit=0&page=3", 10, 20, 3},
+		{"?limit=abc&page=3", 10, 20, 3},
+		{"?limit=-5&page=2", 10, 10, 2},
+		{"?limit=1000&page=2", 100, 100, 2},
+		{"?limit=25&page=0", 25, 0, 1},
+		{"?limit=25&page=x", 25, 0, 1},
+	}
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			req, page, err := pageRequest(c)
+			if err != nil {
+				t.Errorf("%q: %v", tt.query, err)
+			}
+			if req.Limit != tt.limit || req.Offset != tt.offset || page != tt.page {
+				t.Errorf("%q: limit, offset, page = %d, %d, %d; want %d, %d, %d",
+					tt.query, req.Limit, req.Offset, page, tt.limit, tt.offset, tt.page)
+			}
+			return nil
+		})
+		if _, err := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,16 +1,13 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"io"
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/imaging"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
@@ -19,10 +16,11 @@ import (
 type ProfileHandler struct {
 	cfg *config.Config
 	us  service.UserService
+	ns  service.NotificationService
 }
 
-func NewProfileHandler(cfg *config.Config, us service.UserService) *ProfileHandler {
-	return &ProfileHandler{cfg: cfg, us: us}
+func NewProfileHandler(cfg *config.Config, us service.UserService, ns service.NotificationService) *ProfileHandler {
+	return &ProfileHandler{cfg: cfg, us: us, ns: ns}
 }
 
 // @Summary Get my profile
@@ -37,6 +35,11 @@ func (h *ProfileHandler) Me(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
+	counts, err := h.ns.Counts(uid)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	u.Notifications = counts
 	u.PasswordHash = ""
 	return response.OK(c, u)
 }
@@ -51,13 +54,14 @@ func (h *ProfileHandler) Me(c *fiber.Ctx) error {
 // @Router /me [put]
 func (h *ProfileHandler) Update(c *fiber.Ctx) error {
 	var body struct {
-		Name string `json:"name"`
+		Name     string `json:"name"`
+		Timezone string `json:"timezone"` // IANA name, e.g. Asia/Jakarta
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	uid, _ := middleware.GetUserID(c)
-	u, err := h.us.UpdateProfile(uid, body.Name)
+	u, err := h.us.UpdateProfile(uid, body.Name, body.Timezone)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -107,44 +111,26 @@ func (h *ProfileHandler) UploadAvatar(c *fiber.Ctx) error {
 	if fileHeader.Size > 2*1024*1024 {
 		return response.Error(c, fiber.StatusBadRequest, "file too large (max 2MB)")
 	}
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
-	switch ext {
-	case ".png", ".jpg", ".jpeg", ".webp":
-	default:
-		return response.Error(c, fiber.StatusBadRequest, "invalid file type (png|jpg|jpeg|webp)")
-	}
 
 	src, err := fileHeader.Open()
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	defer src.Close()
-
-	uid, _ := middleware.GetUserID(c)
-	filename := fmt.Sprintf("u%d_%d%s", uid, time.Now().UnixNano(), ext)
-	dstPath := filepath.Join(h.cfg.UploadDir, filename)
-
-	if err := os.MkdirAll(h.cfg.UploadDir, 0o755); err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
-	}
-
-	dst, err := os.Create(dstPath)
+	data, err := io.ReadAll(io.LimitReader(src, 2*1024*1024))
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, src); err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	// public URL
-	publicURL := "/uploads/" + filename
-
-	u, err := h.us.UpdateAvatarURL(uid, publicURL)
+	uid, _ := middleware.GetUserID(c)
+	u, err := h.us.UpdateAvatar(uid, data)
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		status := fiber.StatusBadRequest
+		if errors.Is(err, imaging.ErrUnsupportedFormat) {
+			status = fiber.StatusUnsupportedMediaType
+		}
+		return response.Error(c, status, err.Error())
 	}
 	u.PasswordHash = ""
-	return response.OK(c, fiber.Map{"avatar_url": publicURL, "user": u})
+	return response.OK(c, fiber.Map{"avatar_url": u.AvatarURL, "avatar_sizes": u.AvatarSizes, "user": u})
 }
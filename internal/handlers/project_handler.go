@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type ProjectHandler struct {
+	svc service.ProjectService
+}
+
+func NewProjectHandler(s service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{svc: s}
+}
+
+// @Summary List my and shared projects
+// @Security Bearer
+// @Tags Projects
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /projects [get]
+func (h *ProjectHandler) List(c *fiber.Ctx) error {
+	items, err := h.svc.List(actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Get project
+// @Security Bearer
+// @Tags Projects
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /projects/{id} [get]
+func (h *ProjectHandler) Get(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	p, err := h.svc.Get(id, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusNotFound), err.Error())
+	}
+	return response.OK(c, p)
+}
+
+// @Summary Create project
+// @Security Bearer
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param payload body map[string]interface{} true "Project body"
+// @Success 201 {object} map[string]interface{}
+// @Router /projects [post]
+func (h *ProjectHandler) Create(c *fiber.Ctx) error {
+	var input models.Project
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	p, err := h.svc.Create(&input, actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Created(c, p)
+}
+
+// @Summary Update project
+// @Security Bearer
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param payload body map[string]interface{} true "Project body"
+// @Success 200 {object} map[string]interface{}
+// @Router /projects/{id} [put]
+func (h *ProjectHandler) Update(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var input models.Project
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	p, err := h.svc.Update(id, &input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, p)
+}
+
+// @Summary Delete project (owner only, todos are kept)
+// @Security Bearer
+// @Tags Projects
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 204 {string} string "No Content"
+// @Router /projects/{id} [delete]
+func (h *ProjectHandler) Delete(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	if err := h.svc.Delete(id, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
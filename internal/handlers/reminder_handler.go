@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type ReminderHandler struct {
+	svc service.ReminderService
+}
+
+func NewReminderHandler(s service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{svc: s}
+}
+
+// @Summary List reminders of a todo
+// @Security Bearer
+// @Tags Reminders
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/reminders [get]
+func (h *ReminderHandler) List(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	items, err := h.svc.List(todoID, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusNotFound), err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Add reminder
+// @Security Bearer
+// @Tags Reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param payload body map[string]interface{} true "Reminder body"
+// @Success 201 {object} map[string]interface{}
+// @Router /todos/{id}/reminders [post]
+func (h *ReminderHandler) Create(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var input models.Reminder
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	rem, err := h.svc.Create(todoID, &input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.Created(c, rem)
+}
+
+// @Summary Delete reminder
+// @Security Bearer
+// @Tags Reminders
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param reminderId path int true "Reminder ID"
+// @Success 204 {string} string "No Content"
+// @Router /todos/{id}/reminders/{reminderId} [delete]
+func (h *ReminderHandler) Delete(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	remID, err := paramID(c, "reminderId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid reminder id")
+	}
+	if err := h.svc.Delete(todoID, remID, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type ShareHandler struct {
+	svc service.ShareService
+}
+
+func NewShareHandler(s service.ShareService) *ShareHandler {
+	return &ShareHandler{svc: s}
+}
+
+// @Summary List collaborators of a todo
+// @Security Bearer
+// @Tags Sharing
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/shares [get]
+func (h *ShareHandler) ListTodo(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	shares, err := h.svc.ListTodoShares(id, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, shares)
+}
+
+// @Summary Share a todo
+// @Security Bearer
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param payload body map[string]interface{} true "Share body"
+// @Success 201 {object} map[string]interface{}
+// @Router /todos/{id}/shares [post]
+func (h *ShareHandler) ShareTodo(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var input service.ShareInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	share, err := h.svc.ShareTodo(id, input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.Created(c, share)
+}
+
+// @Summary Revoke a todo share
+// @Security Bearer
+// @Tags Sharing
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param userId path int true "Collaborator user ID"
+// @Success 204 {string} string "No Content"
+// @Router /todos/{id}/shares/{userId} [delete]
+func (h *ShareHandler) RevokeTodo(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	userID, err := paramID(c, "userId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid user id")
+	}
+	if err := h.svc.RevokeTodoShare(id, userID, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
+
+// @Summary List collaborators of a project
+// @Security Bearer
+// @Tags Sharing
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /projects/{id}/shares [get]
+func (h *ShareHandler) ListProject(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	shares, err := h.svc.ListProjectShares(id, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, shares)
+}
+
+// @Summary Share a project
+// @Security Bearer
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param payload body map[string]interface{} true "Share body"
+// @Success 201 {object} map[string]interface{}
+// @Router /projects/{id}/shares [post]
+func (h *ShareHandler) ShareProject(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var input service.ShareInput
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	share, err := h.svc.ShareProject(id, input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.Created(c, share)
+}
+
+// @Summary Revoke a project share
+// @Security Bearer
+// @Tags Sharing
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param userId path int true "Collaborator user ID"
+// @Success 204 {string} string "No Content"
+// @Router /projects/{id}/shares/{userId} [delete]
+func (h *ShareHandler) RevokeProject(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	userID, err := paramID(c, "userId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid user id")
+	}
+	if err := h.svc.RevokeProjectShare(id, userID, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"mime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/filter"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/jsonpatch"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
 )
@@ -23,17 +29,40 @@ func NewTodoHandler(s service.TodoService) *TodoHandler {
 // @Security Bearer
 // @Tags Todos
 // @Produce json
-// @Param limit query int false "limit"
-// @Param page query int false "page"
-// @Param q query string false "search"
+// @Param limit query int false "limit (max 100)"
+// @Param page query int false "page (offset pagination; prefer after/before)"
+// @Param after query string false "cursor from meta.next_cursor"
+// @Param before query string false "cursor from meta.prev_cursor"
+// @Param count query bool false "include meta.total (default true unless paging by cursor)"
+// @Param q query string false "full-text search (websearch syntax)"
 // @Param completed query bool false "completed"
 // @Param priority query string false "low|medium|high"
-// @Param sort query string false "created_asc|created_desc|due_asc|due_desc"
+// @Param sort query string false "created_asc|created_desc|due_asc|due_desc|relevance"
+// @Param project_id query int false "project"
+// @Param include_shared query bool false "include todos shared with me"
+// @Param filter query string false "filter expression, e.g. priority in (high,medium) and due_date < now+7d and not completed"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /todos [get]
 func (h *TodoHandler) List(c *fiber.Ctx) error {
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	page, _ := strconv.Atoi(c.Query("page", "1"))
+	filter, err := listFilter(c, h.svc.Now(actorOf(c)))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	pageReq, page, err := pageRequest(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	result, err := h.svc.List(filter, pageReq)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return todoPage(c, result, pageReq, page)
+}
+
+// listFilter reads the list query parameters shared by List and Export.
+// Relative times in the filter expression are resolved against now.
+func listFilter(c *fiber.Ctx, now time.Time) (repository.TodoFilter, error) {
 	search := strings.TrimSpace(c.Query("q", ""))
 
 	var completedPtr *bool
@@ -48,15 +77,36 @@ func (h *TodoHandler) List(c *fiber.Ctx) error {
 		priorityPtr = &p
 	}
 
+	var projectPtr *uint
+	if v := c.Query("project_id", ""); v != "" {
+		id64, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return repository.TodoFilter{}, errors.New("invalid project_id")
+		}
+		id := uint(id64)
+		projectPtr = &id
+	}
+
+	where, err := filter.ParseAt(c.Query("filter", ""), repository.TodoFilterFields, now)
+	if err != nil {
+		return repository.TodoFilter{}, err
+	}
+
 	sort := c.Query("sort", "created_asc")
+	includeShared := c.QueryBool("include_shared", false)
 
 	ownerID, _ := middleware.GetUserID(c)
 
-	items, total, err := h.svc.List(limit, page, search, completedPtr, priorityPtr, sort, &ownerID)
-	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
-	}
-	return response.List(c, items, response.Meta{Limit: limit, Page: page, Total: total})
+	return repository.TodoFilter{
+		Search:        search,
+		Completed:     completedPtr,
+		Priority:      priorityPtr,
+		ProjectID:     projectPtr,
+		Sort:          sort,
+		OwnerID:       &ownerID,
+		IncludeShared: includeShared,
+		Where:         where,
+	}, nil
 }
 
 // @Summary Get todo
@@ -71,10 +121,11 @@ func (h *TodoHandler) Get(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "invalid id")
 	}
-	obj, err := h.svc.Get(uint(id64))
+	obj, err := h.svc.Get(uint(id64), actorOf(c))
 	if err != nil {
-		return response.Error(c, fiber.StatusNotFound, err.Error())
+		return response.Error(c, errorStatus(err, fiber.StatusNotFound), err.Error())
 	}
+	setVersionETag(c, obj.Version)
 	return response.OK(c, obj)
 }
 
@@ -91,11 +142,9 @@ func (h *TodoHandler) Create(c *fiber.Ctx) error {
 	if err := c.BodyParser(&input); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
-	uid, _ := middleware.GetUserID(c)
-	input.OwnerID = uid
-	created, err := h.svc.Create(&input)
+	created, err := h.svc.Create(&input, actorOf(c))
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
 	}
 	return response.Created(c, created)
 }
@@ -106,8 +155,10 @@ func (h *TodoHandler) Create(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "Todo ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param payload body map[string]interface{} true "Todo body"
 // @Success 200 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /todos/{id} [put]
 func (h *TodoHandler) Update(c *fiber.Ctx) error {
 	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
@@ -118,10 +169,53 @@ func (h *TodoHandler) Update(c *fiber.Ctx) error {
 	if err := c.BodyParser(&input); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
-	updated, err := h.svc.Update(uint(id64), &input)
+	updated, err := h.svc.Update(uint(id64), &input, ifMatchVersion(c), actorOf(c))
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	setVersionETag(c, updated.Version)
+	return response.OK(c, updated)
+}
+
+// @Summary Patch todo
+// @Description Partial update. Send application/merge-patch+json (RFC 7396; plain application/json is treated the same) or application/json-patch+json (RFC 6902).
+// @Security Bearer
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param If-Match header string false "ETag from a previous read"
+// @Param payload body map[string]interface{} true "Merge patch object or JSON patch array"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /todos/{id} [patch]
+func (h *TodoHandler) Patch(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var kind service.PatchKind
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	switch mediaType {
+	case "application/merge-patch+json", fiber.MIMEApplicationJSON:
+		kind = service.MergePatch
+	case "application/json-patch+json":
+		kind = service.JSONPatch
+	default:
+		return response.Error(c, fiber.StatusUnsupportedMediaType,
+			"use application/merge-patch+json or application/json-patch+json")
 	}
+	updated, err := h.svc.Patch(id, kind, c.Body(), ifMatchVersion(c), actorOf(c))
+	if err != nil {
+		status := errorStatus(err, fiber.StatusBadRequest)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			status = fiber.StatusConflict
+		}
+		return response.Error(c, status, err.Error())
+	}
+	setVersionETag(c, updated.Version)
 	return response.OK(c, updated)
 }
 
@@ -131,8 +225,10 @@ func (h *TodoHandler) Update(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "Todo ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param payload body map[string]interface{} true "Toggle body"
 // @Success 200 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /todos/{id}/toggle [patch]
 func (h *TodoHandler) Toggle(c *fiber.Ctx) error {
 	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
@@ -145,13 +241,66 @@ func (h *TodoHandler) Toggle(c *fiber.Ctx) error {
 	if err := c.BodyParser(&body); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
-	obj, err := h.svc.ToggleComplete(uint(id64), body.Completed)
+	obj, err := h.svc.ToggleComplete(uint(id64), body.Completed, ifMatchVersion(c), actorOf(c))
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
 	}
+	setVersionETag(c, obj.Version)
 	return response.OK(c, obj)
 }
 
+// @Summary Bulk operation on todos
+// @Description Applies op (complete|reopen|delete|set_priority|move|tag|untag) to the todos selected by ids or filter, in one transaction with a result per todo. delete is admin-only.
+// @Security Bearer
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param payload body map[string]interface{} true "Bulk body"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /todos/bulk [post]
+func (h *TodoHandler) Bulk(c *fiber.Ctx) error {
+	var body struct {
+		service.BulkRequest
+		Where *struct {
+			Q             string           `json:"q"`
+			Completed     *bool            `json:"completed"`
+			Priority      *models.Priority `json:"priority"`
+			ProjectID     *uint            `json:"project_id"`
+			IncludeShared bool             `json:"include_shared"`
+			Expr          string           `json:"expr"`
+		} `json:"filter"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	req := body.BulkRequest
+	if f := body.Where; f != nil {
+		where, err := filter.ParseAt(f.Expr, repository.TodoFilterFields, h.svc.Now(actorOf(c)))
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		ownerID, _ := middleware.GetUserID(c)
+		req.Filter = &repository.TodoFilter{
+			Search:        strings.TrimSpace(f.Q),
+			Completed:     f.Completed,
+			Priority:      f.Priority,
+			ProjectID:     f.ProjectID,
+			OwnerID:       &ownerID,
+			IncludeShared: f.IncludeShared,
+			Where:         where,
+		}
+	}
+	result, err := h.svc.Bulk(req, actorOf(c))
+	if errors.Is(err, service.ErrBulkRolledBack) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"status": false, "error": err.Error(), "data": result})
+	}
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, result)
+}
+
 // @Summary Delete todo (admin only)
 // @Security Bearer
 // @Tags Todos
@@ -164,8 +313,8 @@ func (h *TodoHandler) Delete(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "invalid id")
 	}
-	if err := h.svc.Delete(uint(id64)); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	if err := h.svc.Delete(uint(id64), actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
 	}
 	return response.NoContent(c)
 }
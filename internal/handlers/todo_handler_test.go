@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+)
+
+// clockOnly is a TodoService that only tells the time; any other call
+// panics on the nil embedded interface.
+type clockOnly struct {
+	service.TodoService
+}
+
+func (clockOnly) Now(service.Actor) time.Time { return time.Now() }
+
+// A malformed filter is the client's fault: it must be answered with 400
+// before any query runs, never with a 500 or a panic. The handler's service
+// can only tell the time, so querying it would panic.
+func TestBadFilterIsBadRequest(t *testing.T) {
+	h := NewTodoHandler(clockOnly{})
+	app := fiber.New()
+	app.Get("/todos", h.List)
+	app.Get("/todos/export", h.Export)
+	app.Post("/todos/bulk", h.Bulk)
+
+	for _, expr := range []string{
+		"secret = 1",
+		"priority = urgent",
+		"(completed",
+		"title = 'milk",
+		"completed < true",
+		"due_date < now+7y",
+		"project_id = 99999999999999999999",
+		"not",
+	} {
+		requests := []*http.Request{
+			httptest.NewRequest("GET", "/todos?filter="+url.QueryEscape(expr), nil),
+			httptest.NewRequest("GET", "/todos/export?filter="+url.QueryEscape(expr), nil),
+		}
+		body, _ := json.Marshal(map[string]interface{}{"op": "complete", "filter": map[string]string{"expr": expr}})
+		bulk := httptest.NewRequest("POST", "/todos/bulk", strings.NewReader(string(body)))
+		bulk.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		requests = append(requests, bulk)
+
+		for _, req := range requests {
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+			}
+			if resp.StatusCode != fiber.StatusBadRequest {
+				t.Errorf("%s %s with filter %q: status %d, want 400", req.Method, req.URL.Path, expr, resp.StatusCode)
+			}
+		}
+	}
+}
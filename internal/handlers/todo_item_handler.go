@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type TodoItemHandler struct {
+	svc service.TodoItemService
+}
+
+func NewTodoItemHandler(s service.TodoItemService) *TodoItemHandler {
+	return &TodoItemHandler{svc: s}
+}
+
+func paramID(c *fiber.Ctx, key string) (uint, error) {
+	id64, err := strconv.ParseUint(c.Params(key), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id64), nil
+}
+
+// @Summary List checklist items
+// @Security Bearer
+// @Tags Todo Items
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/items [get]
+func (h *TodoItemHandler) List(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	items, err := h.svc.List(todoID, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusNotFound), err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Add checklist item
+// @Security Bearer
+// @Tags Todo Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param payload body map[string]interface{} true "Item body"
+// @Success 201 {object} map[string]interface{}
+// @Router /todos/{id}/items [post]
+func (h *TodoItemHandler) Create(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var input models.TodoItem
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	item, err := h.svc.Create(todoID, &input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.Created(c, item)
+}
+
+// @Summary Update checklist item
+// @Security Bearer
+// @Tags Todo Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param itemId path int true "Item ID"
+// @Param payload body map[string]interface{} true "Item body"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/items/{itemId} [put]
+func (h *TodoItemHandler) Update(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	itemID, err := paramID(c, "itemId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid item id")
+	}
+	var input models.TodoItem
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	item, err := h.svc.Update(todoID, itemID, &input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, item)
+}
+
+// @Summary Toggle checklist item
+// @Security Bearer
+// @Tags Todo Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param itemId path int true "Item ID"
+// @Param payload body map[string]interface{} true "Toggle body"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/items/{itemId}/toggle [patch]
+func (h *TodoItemHandler) Toggle(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	itemID, err := paramID(c, "itemId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid item id")
+	}
+	var body struct {
+		Completed bool `json:"completed"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	item, todo, err := h.svc.Toggle(todoID, itemID, body.Completed, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, fiber.Map{"item": item, "todo": todo})
+}
+
+// @Summary Reorder checklist items
+// @Security Bearer
+// @Tags Todo Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param payload body map[string]interface{} true "Order body"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/{id}/items/order [put]
+func (h *TodoItemHandler) Reorder(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var body struct {
+		IDs []uint `json:"ids"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	items, err := h.svc.Reorder(todoID, body.IDs, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Delete checklist item
+// @Security Bearer
+// @Tags Todo Items
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param itemId path int true "Item ID"
+// @Success 204 {string} string "No Content"
+// @Router /todos/{id}/items/{itemId} [delete]
+func (h *TodoItemHandler) Delete(c *fiber.Ctx) error {
+	todoID, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	itemID, err := paramID(c, "itemId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid item id")
+	}
+	if err := h.svc.Delete(todoID, itemID, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
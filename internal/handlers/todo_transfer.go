@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+// @Summary Export todos
+// @Description Streams every todo matching the list filters (q, completed, priority, project_id, include_shared, filter, sort) as CSV, JSON or iCalendar.
+// @Security Bearer
+// @Tags Todos
+// @Produce text/csv,application/json,text/calendar
+// @Param format query string false "csv|json|ics (default json)"
+// @Success 200 {string} string "file"
+// @Router /todos/export [get]
+func (h *TodoHandler) Export(c *fiber.Ctx) error {
+	format, err := todoio.ParseFormat(c.Query("format", "json"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	filter, err := listFilter(c, h.svc.Now(actorOf(c)))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="todos.`+string(format)+`"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc, err := todoio.NewEncoder(format, w)
+		if err == nil {
+			err = h.svc.Export(filter, func(todos []models.Todo) error {
+				for i := range todos {
+					if err := enc.Encode(&todos[i]); err != nil {
+						return err
+					}
+				}
+				return w.Flush()
+			})
+			if cerr := enc.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			log.Printf("export: %v", err)
+		}
+	})
+	return nil
+}
+
+// @Summary Import todos
+// @Description Creates todos from a CSV, JSON or iCalendar file sent as the body or as multipart field "file". Rows whose external_id (iCalendar UID) matches an existing todo update it instead. With dry_run=true nothing is saved.
+// @Security Bearer
+// @Tags Todos
+// @Accept text/csv,application/json,text/calendar,multipart/form-data
+// @Produce json
+// @Param format query string false "csv|json|ics (default: from the content type or file name)"
+// @Param dry_run query bool false "validate only"
+// @Success 200 {object} map[string]interface{}
+// @Router /todos/import [post]
+func (h *TodoHandler) Import(c *fiber.Ctx) error {
+	body, contentType, name, err := uploadedFile(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	defer body.Close()
+
+	format, ok := todoio.FormatOfContentType(contentType)
+	if !ok && name != "" {
+		if f, err := todoio.ParseFormat(strings.TrimPrefix(filepath.Ext(name), ".")); err == nil {
+			format, ok = f, true
+		}
+	}
+	if v := c.Query("format"); v != "" || !ok {
+		if format, err = todoio.ParseFormat(v); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	records, err := todoio.Decode(format, body)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	result, err := h.svc.Import(records, c.QueryBool("dry_run", false), actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.OK(c, result)
+}
+
+// uploadedFile returns the file sent as the request body, or as multipart
+// field "file" together with its content type and name.
+func uploadedFile(c *fiber.Ctx) (io.ReadCloser, string, string, error) {
+	contentType := c.Get(fiber.HeaderContentType)
+	if !strings.HasPrefix(contentType, fiber.MIMEMultipartForm) {
+		return io.NopCloser(bytes.NewReader(c.Body())), contentType, "", nil
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", "", errors.New("file is required")
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, "", "", err
+	}
+	return f, fh.Header.Get(fiber.HeaderContentType), fh.Filename, nil
+}
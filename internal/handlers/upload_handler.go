@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+// UploadHandler serves stored files below /uploads. Signed URLs are always
+// honored; unsigned requests are refused when signing is enabled, and
+// attachments are never served unsigned.
+type UploadHandler struct {
+	blobs storage.BlobStore
+	urls  *storage.URLSigner
+}
+
+func NewUploadHandler(blobs storage.BlobStore, urls *storage.URLSigner) *UploadHandler {
+	return &UploadHandler{blobs: blobs, urls: urls}
+}
+
+func (h *UploadHandler) Serve(c *fiber.Ctx) error {
+	key := c.Params("*")
+	if key == "" || strings.Contains(key, "..") {
+		return response.Error(c, fiber.StatusNotFound, "not found")
+	}
+
+	cacheControl := "public, max-age=86400"
+	disposition := ""
+	if sig := c.Query("sig"); sig != "" || h.urls.Enabled() {
+		name := c.Query("name")
+		expires, err := h.urls.Verify(key, c.Query("exp"), name, sig)
+		if err != nil {
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(time.Until(expires).Seconds()))
+		if name != "" {
+			disposition = mime.FormatMediaType("attachment", map[string]string{"filename": name})
+		}
+	} else if strings.HasPrefix(key, "attachments/") {
+		return response.Error(c, fiber.StatusNotFound, "not found")
+	}
+
+	ctx := context.Background()
+	info, err := h.blobs.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return response.Error(c, fiber.StatusNotFound, "not found")
+		}
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderCacheControl, cacheControl)
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if info.ETag != "" {
+		c.Set(fiber.HeaderETag, info.ETag)
+	}
+	if !info.ModTime.IsZero() {
+		c.Set(fiber.HeaderLastModified, info.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if info.ETag != "" && etagMatches(c.Get(fiber.HeaderIfNoneMatch), info.ETag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, info.ContentType)
+	if disposition != "" {
+		c.Set(fiber.HeaderContentDisposition, disposition)
+	}
+
+	start, length := int64(0), info.Size
+	status := fiber.StatusOK
+	if rh := c.Get(fiber.HeaderRange); rh != "" && ifRangeMatches(c.Get(fiber.HeaderIfRange), info) {
+		s, n, ok, err := parseRange(rh, info.Size)
+		if err != nil {
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", info.Size))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		}
+		if ok {
+			start, length, status = s, n, fiber.StatusPartialContent
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", s, s+n-1, info.Size))
+		}
+	}
+
+	c.Status(status)
+	if c.Method() == fiber.MethodHead {
+		c.Response().Header.SetContentLength(int(length))
+		return nil
+	}
+	if status == fiber.StatusPartialContent {
+		rc, _, err := h.blobs.GetRange(ctx, key, start, length)
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return c.SendStream(rc, int(length))
+	}
+	rc, _, err := h.blobs.Get(ctx, key)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return c.SendStream(rc, int(length))
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using
+// weak comparison.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// ifRangeMatches reports whether a range request may be honored: either
+// there is no If-Range precondition or it still matches the object.
+func ifRangeMatches(header string, info *storage.ObjectInfo) bool {
+	if header == "" {
+		return true
+	}
+	if strings.HasPrefix(header, `"`) {
+		return header == info.ETag
+	}
+	t, err := http.ParseTime(header)
+	return err == nil && !info.ModTime.IsZero() && !info.ModTime.Truncate(time.Second).After(t)
+}
+
+// parseRange parses a single-range "bytes=" header. ok is false when the
+// header should be ignored and the whole object served; err is set when the
+// range cannot be satisfied.
+func parseRange(header string, size int64) (start, length int64, ok bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, nil
+	}
+	errUnsatisfiable := errors.New("range not satisfiable")
+	if first == "" {
+		// Suffix range: the last n bytes.
+		n, perr := strconv.ParseInt(last, 10, 64)
+		if perr != nil || n < 0 {
+			return 0, 0, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, errUnsatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true, nil
+	}
+	s, perr := strconv.ParseInt(first, 10, 64)
+	if perr != nil || s < 0 {
+		return 0, 0, false, nil
+	}
+	if s >= size {
+		return 0, 0, false, errUnsatisfiable
+	}
+	e := size - 1
+	if last != "" {
+		v, perr := strconv.ParseInt(last, 10, 64)
+		if perr != nil || v < s {
+			return 0, 0, false, nil
+		}
+		if v < e {
+			e = v
+		}
+	}
+	return s, e - s + 1, true, nil
+}
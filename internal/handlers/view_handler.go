@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type ViewHandler struct {
+	svc service.ViewService
+}
+
+func NewViewHandler(s service.ViewService) *ViewHandler {
+	return &ViewHandler{svc: s}
+}
+
+// @Summary List system and saved views
+// @Security Bearer
+// @Tags Views
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /views [get]
+func (h *ViewHandler) List(c *fiber.Ctx) error {
+	items, err := h.svc.List(actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Get view
+// @Security Bearer
+// @Tags Views
+// @Produce json
+// @Param id path string true "View ID or system key (today|upcoming|overdue)"
+// @Success 200 {object} map[string]interface{}
+// @Router /views/{id} [get]
+func (h *ViewHandler) Get(c *fiber.Ctx) error {
+	v, err := h.svc.Get(c.Params("id"), actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusNotFound), err.Error())
+	}
+	return response.OK(c, v)
+}
+
+// @Summary Save a view
+// @Security Bearer
+// @Tags Views
+// @Accept json
+// @Produce json
+// @Param payload body map[string]interface{} true "View body (name, q, filter, sort, project_id, include_shared)"
+// @Success 201 {object} map[string]interface{}
+// @Router /views [post]
+func (h *ViewHandler) Create(c *fiber.Ctx) error {
+	var input models.SavedView
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	v, err := h.svc.Create(&input, actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Created(c, v)
+}
+
+// @Summary Update a saved view
+// @Security Bearer
+// @Tags Views
+// @Accept json
+// @Produce json
+// @Param id path int true "View ID"
+// @Param payload body map[string]interface{} true "View body"
+// @Success 200 {object} map[string]interface{}
+// @Router /views/{id} [put]
+func (h *ViewHandler) Update(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	var input models.SavedView
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	v, err := h.svc.Update(id, &input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, v)
+}
+
+// @Summary Delete a saved view
+// @Security Bearer
+// @Tags Views
+// @Produce json
+// @Param id path int true "View ID"
+// @Success 204 {string} string "No Content"
+// @Router /views/{id} [delete]
+func (h *ViewHandler) Delete(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid id")
+	}
+	if err := h.svc.Delete(id, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.NoContent(c)
+}
+
+// @Summary List the todos of a view
+// @Security Bearer
+// @Tags Views
+// @Produce json
+// @Param id path string true "View ID or system key (today|upcoming|overdue)"
+// @Param limit query int false "limit (max 100)"
+// @Param page query int false "page"
+// @Param after query string false "cursor from meta.next_cursor"
+// @Param before query string false "cursor from meta.prev_cursor"
+// @Param count query bool false "include meta.total"
+// @Success 200 {object} map[string]interface{}
+// @Router /views/{id}/todos [get]
+func (h *ViewHandler) Todos(c *fiber.Ctx) error {
+	pageReq, page, err := pageRequest(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	result, err := h.svc.Todos(c.Params("id"), pageReq, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return todoPage(c, result, pageReq, page)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/pkg/response"
+)
+
+type WebhookHandler struct {
+	svc service.WebhookService
+}
+
+func NewWebhookHandler(s service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: s}
+}
+
+// webhookWithSecret shows the signing secret, which is only done when it
+// is created or rotated.
+type webhookWithSecret struct {
+	*models.Webhook
+	Secret string `json:"secret"`
+}
+
+// @Summary List my webhooks
+// @Security Bearer
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(c *fiber.Ctx) error {
+	hooks, err := h.svc.List(actorOf(c))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.OK(c, hooks)
+}
+
+// @Summary Register a webhook
+// @Description Subscribes url to events (todo.created, todo.updated, todo.completed, todo.deleted, user.created, user.updated) about the caller's data. global=true (admins only) receives them for all users. The response holds the signing secret, which is not shown again.
+// @Security Bearer
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param payload body map[string]interface{} true "url, events, description, global"
+// @Success 201 {object} map[string]interface{}
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c *fiber.Ctx) error {
+	var input models.Webhook
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	w, err := h.svc.Create(&input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.Created(c, webhookWithSecret{Webhook: w, Secret: w.Secret})
+}
+
+// @Summary Get a webhook
+// @Security Bearer
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) Get(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	w, err := h.svc.Get(id, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.OK(c, w)
+}
+
+// @Summary Update a webhook
+// @Description Replaces url, events, description and global; active (default true) pauses or resumes deliveries.
+// @Security Bearer
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param payload body map[string]interface{} true "url, events, description, global, active"
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	input := models.Webhook{Active: true}
+	if err := c.BodyParser(&input); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	w, err := h.svc.Update(id, &input, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, w)
+}
+
+// @Summary Delete a webhook and its delivery log
+// @Security Bearer
+// @Tags Webhooks
+// @Param id path int true "Webhook ID"
+// @Success 204
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	if err := h.svc.Delete(id, actorOf(c)); err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.NoContent(c)
+}
+
+// @Summary Rotate a webhook's signing secret
+// @Security Bearer
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks/{id}/secret [post]
+func (h *WebhookHandler) RotateSecret(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	w, err := h.svc.RotateSecret(id, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.OK(c, webhookWithSecret{Webhook: w, Secret: w.Secret})
+}
+
+// @Summary List a webhook's deliveries
+// @Security Bearer
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "pending|succeeded|dead"
+// @Param before query int false "only deliveries with a lower ID (paging)"
+// @Param limit query int false "max 100"
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	before := c.QueryInt("before", 0)
+	if before < 0 {
+		before = 0
+	}
+	items, err := h.svc.Deliveries(id, models.DeliveryStatus(c.Query("status")), uint(before), c.QueryInt("limit", 50), actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusBadRequest), err.Error())
+	}
+	return response.OK(c, items)
+}
+
+// @Summary Get a delivery with its attempt log
+// @Security Bearer
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) Delivery(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	deliveryID, err := paramID(c, "deliveryId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	d, err := h.svc.Delivery(id, deliveryID, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.OK(c, d)
+}
+
+// @Summary Redeliver a delivery
+// @Description Queues the same payload again with a fresh set of attempts, including dead deliveries.
+// @Security Bearer
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	deliveryID, err := paramID(c, "deliveryId")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	d, err := h.svc.Redeliver(id, deliveryID, actorOf(c))
+	if err != nil {
+		return response.Error(c, errorStatus(err, fiber.StatusInternalServerError), err.Error())
+	}
+	return response.Accepted(c, d)
+}
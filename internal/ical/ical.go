@@ -0,0 +1,374 @@
+// Package ical reads and writes iCalendar (RFC 5545) content: components,
+// properties with parameters, line folding and text escaping. It knows
+// nothing about todos; see package todoio for the VTODO mapping.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Prop is a content line such as DUE;TZID=Asia/Jakarta:20240501T090000.
+// Value is kept raw; use Text for TEXT values.
+type Prop struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Text returns the value with TEXT escapes removed.
+func (p *Prop) Text() string {
+	return UnescapeText(p.Value)
+}
+
+// Component is a BEGIN/END block with its properties and subcomponents.
+type Component struct {
+	Name       string
+	Props      []Prop
+	Components []*Component
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add appends a property with a raw value.
+func (c *Component) Add(name, value string, params ...string) {
+	p := Prop{Name: name, Value: value}
+	for i := 0; i+1 < len(params); i += 2 {
+		if p.Params == nil {
+			p.Params = map[string]string{}
+		}
+		p.Params[params[i]] = params[i+1]
+	}
+	c.Props = append(c.Props, p)
+}
+
+// AddText appends a TEXT property, escaping value.
+func (c *Component) AddText(name, value string) {
+	c.Add(name, EscapeText(value))
+}
+
+// Get returns the first property called name, or nil.
+func (c *Component) Get(name string) *Prop {
+	for i := range c.Props {
+		if strings.EqualFold(c.Props[i].Name, name) {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+// GetAll returns every property called name.
+func (c *Component) GetAll(name string) []Prop {
+	var out []Prop
+	for _, p := range c.Props {
+		if strings.EqualFold(p.Name, name) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Text returns the unescaped value of the first property called name.
+func (c *Component) Text(name string) string {
+	if p := c.Get(name); p != nil {
+		return p.Text()
+	}
+	return ""
+}
+
+// Children returns the direct subcomponents called name.
+func (c *Component) Children(name string) []*Component {
+	var out []*Component
+	for _, sub := range c.Components {
+		if strings.EqualFold(sub.Name, name) {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+func EscapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+func UnescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// SplitText splits a multi-valued TEXT property (e.g. CATEGORIES) on
+// unescaped commas and unescapes each value.
+func SplitText(s string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			out = append(out, UnescapeText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(out, UnescapeText(s[start:]))
+}
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	dateFormat     = "20060102"
+)
+
+// FormatTime formats t as a UTC DATE-TIME.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// ParseTime parses a DATE or DATE-TIME property. Floating times and
+// dates are taken in loc unless the property has a TZID that loads.
+func ParseTime(p *Prop, loc *time.Location) (time.Time, error) {
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	v := strings.TrimSpace(p.Value)
+	switch {
+	case strings.HasSuffix(v, "Z"):
+		return time.Parse(dateTimeFormat, v)
+	case len(v) == len(dateFormat):
+		return time.ParseInLocation(dateFormat, v, loc)
+	}
+	return time.ParseInLocation("20060102T150405", v, loc)
+}
+
+// Writer writes components as folded CRLF content lines.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Begin opens a component whose properties and children follow.
+func (w *Writer) Begin(name string) {
+	w.line("BEGIN:" + name)
+}
+
+func (w *Writer) End(name string) {
+	w.line("END:" + name)
+}
+
+// Prop writes a single property.
+func (w *Writer) Prop(p Prop) {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	keys := make([]string, 0, len(p.Params))
+	for k := range p.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := p.Params[k]
+		if strings.ContainsAny(v, ":;,") {
+			v = `"` + strings.ReplaceAll(v, `"`, "") + `"`
+		}
+		b.WriteString(";" + k + "=" + v)
+	}
+	b.WriteString(":" + p.Value)
+	w.line(b.String())
+}
+
+// Component writes c and everything below it.
+func (w *Writer) Component(c *Component) {
+	w.Begin(c.Name)
+	for _, p := range c.Props {
+		w.Prop(p)
+	}
+	for _, sub := range c.Components {
+		w.Component(sub)
+	}
+	w.End(c.Name)
+}
+
+// Flush writes buffered lines and reports the first error.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// line writes s folded at 75 octets without splitting UTF-8 sequences.
+func (w *Writer) line(s string) {
+	if w.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, w.err = w.w.WriteString(s[:cut] + "\r\n "); w.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = 74
+	}
+	_, w.err = w.w.WriteString(s + "\r\n")
+}
+
+// Encode writes a single component tree.
+func Encode(w io.Writer, c *Component) error {
+	iw := NewWriter(w)
+	iw.Component(c)
+	return iw.Flush()
+}
+
+// MaxLineLength bounds unfolded content lines accepted by Decode.
+const MaxLineLength = 1 << 16
+
+var ErrMalformed = errors.New("ical: malformed content")
+
+// Decode reads one top-level component, typically a VCALENDAR.
+func Decode(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var stack []*Component
+	var root *Component
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrMalformed, n+1, err)
+		}
+		switch strings.ToUpper(p.Name) {
+		case "BEGIN":
+			c := NewComponent(strings.ToUpper(p.Value))
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, c)
+			} else if root != nil {
+				return nil, fmt.Errorf("%w: line %d: more than one top-level component", ErrMalformed, n+1)
+			} else {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || !strings.EqualFold(stack[len(stack)-1].Name, p.Value) {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrMalformed, n+1, p.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: line %d: property outside a component", ErrMalformed, n+1)
+			}
+			top := stack[len(stack)-1]
+			top.Props = append(top.Props, p)
+		}
+	}
+	if root == nil || len(stack) > 0 {
+		return nil, fmt.Errorf("%w: missing BEGIN or END", ErrMalformed)
+	}
+	return root, nil
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), MaxLineLength)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			last := &lines[len(lines)-1]
+			if len(*last)+len(line) > MaxLineLength {
+				return nil, fmt.Errorf("%w: line too long", ErrMalformed)
+			}
+			*last += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return lines, nil
+}
+
+// parseLine splits name *(;param=value) : value, honouring quoted
+// parameter values.
+func parseLine(line string) (Prop, error) {
+	var p Prop
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, errors.New("missing name")
+	}
+	p.Name = strings.ToUpper(line[:i])
+	for line[i] == ';' {
+		line = line[i+1:]
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return p, errors.New("malformed parameter")
+		}
+		key := strings.ToUpper(line[:eq])
+		line = line[eq+1:]
+		var val string
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return p, errors.New("unterminated quoted parameter")
+			}
+			val, line = line[1:end+1], line[end+2:]
+			i = 0
+		} else {
+			i = strings.IndexAny(line, ";:")
+			if i < 0 {
+				return p, errors.New("missing value")
+			}
+			val = line[:i]
+			line = line[i:]
+			i = 0
+		}
+		if len(line) == 0 {
+			return p, errors.New("missing value")
+		}
+		if p.Params == nil {
+			p.Params = map[string]string{}
+		}
+		p.Params[key] = val
+	}
+	if line[i] != ':' {
+		return p, errors.New("missing value")
+	}
+	p.Value = line[i+1:]
+	return p, nil
+}
@@ -0,0 +1,215 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+func TestTextEscapes(t *testing.T) {
+	tests := []struct {
+		text, escaped string
+	}{
+		{"plain", "plain"},
+		{"milk, eggs; bread", `milk\, eggs\; bread`},
+		{`C:\temp`, `C:\\temp`},
+		{"line one\nline two", `line one\nline two`},
+		{"windows\r\nline", `windows\nline`},
+		{`\n is not a newline`, `\\n is not a newline`},
+		{"ümlaut, 日本語", `ümlaut\, 日本語`},
+	}
+	for _, tt := range tests {
+		if got := EscapeText(tt.text); got != tt.escaped {
+			t.Errorf("EscapeText(%q) = %q, want %q", tt.text, got, tt.escaped)
+		}
+		want := strings.ReplaceAll(tt.text, "\r\n", "\n")
+		if got := UnescapeText(tt.escaped); got != want {
+			t.Errorf("UnescapeText(%q) = %q, want %q", tt.escaped, got, want)
+		}
+	}
+	// Other writers use \N and escape characters that need no escaping.
+	if got := UnescapeText(`a\Nb\:c`); got != "a\nb:c" {
+		t.Errorf("UnescapeText = %q", got)
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"work", []string{"work"}},
+		{"work,home", []string{"work", "home"}},
+		{`a\,b,c`, []string{"a,b", "c"}},
+		{`back\\,slash`, []string{`back\`, "slash"}},
+		{"a,,b", []string{"a", "", "b"}},
+	}
+	for _, tt := range tests {
+		if got := SplitText(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFolding(t *testing.T) {
+	summary := strings.Repeat("Déjà vu — 日本語のタスク, ", 12)
+	c := NewComponent("VTODO")
+	c.AddText("SUMMARY", summary)
+	c.Add("X-LONG-ASCII", strings.Repeat("x", 300))
+	var buf bytes.Buffer
+	if err := Encode(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Fatal("output does not end with CRLF")
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	if len(lines) < 8 {
+		t.Fatalf("expected folded lines, got %d lines", len(lines))
+	}
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i+1, len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 sequence: %q", i+1, line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("line %d contains a bare newline", i+1)
+		}
+	}
+
+	got, err := Decode(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.Text("SUMMARY"); s != summary {
+		t.Errorf("SUMMARY = %q, want %q", s, summary)
+	}
+	if s := got.Text("X-LONG-ASCII"); s != strings.Repeat("x", 300) {
+		t.Errorf("X-LONG-ASCII has %d characters, want 300", len(s))
+	}
+}
+
+func TestDecodeUnfolds(t *testing.T) {
+	// RFC 5545, section 3.1, with a tab continuation and LF-only endings
+	// mixed in.
+	src := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"DESCRIPTION:This is a lo\r\n" +
+		" ng description\r\n" +
+		"  that exists on a long line.\n" +
+		"SUMMARY:tab\n" +
+		"\tbed\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	cal, err := Decode(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	todos := cal.Children("VTODO")
+	if len(todos) != 1 {
+		t.Fatalf("got %d VTODOs", len(todos))
+	}
+	if got, want := todos[0].Text("DESCRIPTION"), "This is a long description that exists on a long line."; got != want {
+		t.Errorf("DESCRIPTION = %q, want %q", got, want)
+	}
+	if got := todos[0].Text("SUMMARY"); got != "tabbed" {
+		t.Errorf("SUMMARY = %q, want tabbed", got)
+	}
+}
+
+func TestParams(t *testing.T) {
+	src := "BEGIN:VTODO\r\n" +
+		`ATTENDEE;CN="Doe, John";role=REQ-PARTICIPANT:mailto:john@example.com` + "\r\n" +
+		"DUE;VALUE=DATE:20240501\r\n" +
+		"END:VTODO\r\n"
+	c, err := Decode(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := c.Get("attendee")
+	if p == nil {
+		t.Fatal("ATTENDEE missing")
+	}
+	want := Prop{
+		Name:   "ATTENDEE",
+		Params: map[string]string{"CN": "Doe, John", "ROLE": "REQ-PARTICIPANT"},
+		Value:  "mailto:john@example.com",
+	}
+	if !reflect.DeepEqual(*p, want) {
+		t.Errorf("ATTENDEE = %+v, want %+v", *p, want)
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `ATTENDEE;CN="Doe, John";ROLE=REQ-PARTICIPANT:mailto:john@example.com`) {
+		t.Errorf("parameters not written back:\n%s", buf.String())
+	}
+	again, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, c) {
+		t.Errorf("round trip changed the component:\n%+v\n%+v", again, c)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		prop Prop
+		want time.Time
+	}{
+		{Prop{Value: "20240501T090000Z"}, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)},
+		{Prop{Value: "20240501T090000"}, time.Date(2024, 5, 1, 9, 0, 0, 0, jakarta)},
+		{Prop{Value: "20240501"}, time.Date(2024, 5, 1, 0, 0, 0, 0, jakarta)},
+		{Prop{Value: "20240501T090000", Params: map[string]string{"TZID": "America/New_York"}},
+			time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)},
+		{Prop{Value: "20240501T090000", Params: map[string]string{"TZID": "Nowhere/Special"}},
+			time.Date(2024, 5, 1, 9, 0, 0, 0, jakarta)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(&tt.prop, jakarta)
+		if err != nil {
+			t.Errorf("ParseTime(%+v): %v", tt.prop, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%+v) = %v, want %v", tt.prop, got, tt.want)
+		}
+	}
+	if _, err := ParseTime(&Prop{Value: "tomorrow"}, time.UTC); err == nil {
+		t.Error("ParseTime accepted an invalid value")
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"BEGIN:VCALENDAR\r\n",
+		"END:VCALENDAR\r\n",
+		"SUMMARY:outside\r\n",
+		"BEGIN:VCALENDAR\r\nEND:VTODO\r\n",
+		"BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\nBEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nno colon here\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nX;CN=\"open:x\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nX;=y:z\r\nEND:VCALENDAR\r\n",
+	} {
+		if _, err := Decode(strings.NewReader(src)); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Decode(%q) error = %v, want ErrMalformed", src, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package imaging validates and normalizes user-supplied images.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the webp decoder
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format (png|jpeg|webp)")
+	ErrTooManyPixels     = errors.New("image dimensions too large")
+)
+
+// MaxPixels bounds width*height before a full decode so that small but
+// highly compressed files cannot exhaust memory.
+const MaxPixels = 40_000_000
+
+// Sniff identifies an image by its magic bytes and returns "png", "jpeg",
+// "webp" or "" for anything else.
+func Sniff(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case len(head) >= 12 && string(head[0:4]) == "RIFF" && string(head[8:12]) == "WEBP":
+		return "webp"
+	}
+	return ""
+}
+
+// Decode checks the magic bytes and dimensions of data and decodes it into
+// pixels only. Metadata such as EXIF is dropped; a JPEG's orientation tag
+// is applied first so photos stay upright.
+func Decode(data []byte) (image.Image, error) {
+	format := Sniff(data)
+	if format == "" {
+		return nil, ErrUnsupportedFormat
+	}
+	cfg, decoded, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if decoded != format {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// Square center-crops img to a square and scales it to size x size.
+func Square(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	src := image.Rect(x0, y0, x0+side, y0+side)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+// Encode writes img as PNG when it has transparency and as JPEG otherwise.
+// It returns the file extension and content type used.
+func Encode(w io.Writer, img image.Image) (ext, contentType string, err error) {
+	if opaque(img) {
+		return ".jpg", "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	}
+	return ".png", "image/png", png.Encode(w, img)
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+// Package importer converts exports of other task managers (Todoist,
+// Trello, Microsoft To Do) into todoio records, so they go through the
+// regular todo import. Source projects, boards and lists become projects,
+// labels become tags, sub-tasks and checklists become checklist items.
+// Whatever has no equivalent is reported as skipped or as a warning on
+// the record.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+)
+
+type Source string
+
+const (
+	Todoist Source = "todoist"
+	Trello  Source = "trello"
+	MSToDo  Source = "mstodo"
+)
+
+// MaxTasks caps how many tasks one export may contain.
+const MaxTasks = 10000
+
+// Field limits of models.Todo, models.TodoItem and tags; longer values
+// are cut with a warning instead of failing the row.
+const (
+	maxTitle       = 200
+	maxDescription = 2000
+	maxTag         = 50
+)
+
+func ParseSource(s string) (Source, error) {
+	switch src := Source(strings.ToLower(s)); src {
+	case Todoist, Trello, MSToDo:
+		return src, nil
+	}
+	return "", fmt.Errorf("unknown source %q (want todoist, trello or mstodo)", s)
+}
+
+// Skipped is a piece of the export that was not imported at all.
+type Skipped struct {
+	Ref    string `json:"ref"`
+	Reason string `json:"reason"`
+}
+
+type Result struct {
+	Records []todoio.Record
+	Skipped []Skipped
+}
+
+// Parse reads an export of src. name is the file name, which Todoist CSV
+// exports (one project per file) use as the project name. Dates without
+// a time or zone are taken in loc.
+func Parse(src Source, name string, data []byte, loc *time.Location) (*Result, error) {
+	switch src {
+	case Todoist:
+		return parseTodoist(name, data, loc)
+	case Trello:
+		return parseTrello(data, loc)
+	case MSToDo:
+		return parseMSToDo(data, loc)
+	}
+	return nil, fmt.Errorf("unknown source %q", src)
+}
+
+func (r *Result) skip(ref, format string, args ...interface{}) {
+	r.Skipped = append(r.Skipped, Skipped{Ref: ref, Reason: fmt.Sprintf(format, args...)})
+}
+
+// add appends a record, numbering it and enforcing MaxTasks.
+func (r *Result) add(rec *task) error {
+	if len(r.Records) >= MaxTasks {
+		return fmt.Errorf("too many tasks (max %d)", MaxTasks)
+	}
+	rec.Row = len(r.Records) + 1
+	r.Records = append(r.Records, rec.Record)
+	return nil
+}
+
+// task builds one record.
+type task struct {
+	todoio.Record
+}
+
+func newTask(source Source, id, title, project string) *task {
+	t := &task{}
+	if id != "" {
+		t.ExternalID = string(source) + ":" + id
+	}
+	t.Project = clip(strings.TrimSpace(project), 120)
+	t.Todo.Title = t.clip("title", strings.TrimSpace(title), maxTitle)
+	return t
+}
+
+func (t *task) warn(format string, args ...interface{}) {
+	t.Warnings = append(t.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (t *task) clip(field, s string, max int) string {
+	if len(s) > max {
+		t.warn("%s cut to %d characters", field, max)
+		return clip(s, max)
+	}
+	return s
+}
+
+func (t *task) describe(s string) {
+	t.Todo.Description = t.clip("description", strings.TrimSpace(s), maxDescription)
+}
+
+func (t *task) tag(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	t.Tags = append(t.Tags, t.clip("tag", name, maxTag))
+}
+
+func (t *task) item(title string, done bool) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return
+	}
+	t.Todo.Items = append(t.Todo.Items, models.TodoItem{Title: t.clip("checklist item", title, maxTitle), Completed: done})
+}
+
+// clip cuts s to at most max bytes without splitting a UTF-8 sequence.
+func clip(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8Start(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+func utf8Start(b byte) bool { return b&0xC0 != 0x80 }
+
+// dateLayouts are tried in order by parseDate; layouts without a zone
+// are read in the caller's location.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.9999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"Jan 2 2006 15:04",
+	"Jan 2 2006",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+}
+
+func parseDate(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// priorityOfName maps label names that read as priorities.
+func priorityOfName(name string) (models.Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "high", "high priority", "urgent", "critical", "important", "p1":
+		return models.PriorityHigh, true
+	case "medium", "medium priority", "normal", "p2":
+		return models.PriorityMedium, true
+	case "low", "low priority", "p3", "p4":
+		return models.PriorityLow, true
+	}
+	return "", false
+}
+
+var htmlTag = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// plainText turns a simple HTML body into text.
+func plainText(s string) string {
+	s = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n", "</div>", "\n").Replace(s)
+	return strings.TrimSpace(html.UnescapeString(htmlTag.ReplaceAllString(s, "")))
+}
+
+// flexID accepts IDs written as JSON strings or numbers.
+type flexID string
+
+func (id *flexID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*id = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*id = flexID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("invalid id %s", b)
+	}
+	*id = flexID(n.String())
+	return nil
+}
@@ -0,0 +1,277 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"unicode/utf8"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+)
+
+// want describes an expected record; Warnings are substrings, in order.
+type want struct {
+	ExternalID  string
+	Project     string
+	Title       string
+	Description string
+	Priority    models.Priority
+	Completed   bool
+	Due         time.Time
+	Tags        []string
+	Items       []models.TodoItem
+	Warnings    []string
+}
+
+func item(title string, done bool) models.TodoItem {
+	return models.TodoItem{Title: title, Completed: done}
+}
+
+func TestParseFixtures(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, jakarta) }
+	utc := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		source  Source
+		fixture string
+		file    string // name passed to Parse; the fixture's name if empty
+		records []want
+		skipped []Skipped
+	}{
+		{
+			name: "todoist sync", source: Todoist, fixture: "todoist_sync.json",
+			records: []want{
+				{
+					ExternalID: "todoist:6X7rM8997g3RQmvh", Project: "Inbox", Title: "Buy groceries",
+					Description: "From the market", Priority: models.PriorityHigh, Due: utc(2024, 5, 1, 9),
+					Tags:     []string{"errands", "home"},
+					Items:    []models.TodoItem{item("Milk", true), item("Oat milk", false)},
+					Warnings: []string{`sub-task "Oat milk": only its title and state were kept`},
+				},
+				{
+					ExternalID: "todoist:6X7rfEVP8hvv25ZR", Project: "Inbox", Title: "Water plants",
+					Due: in(2024, 5, 2, 0), Warnings: []string{`recurrence "every day" not imported`},
+				},
+				{
+					ExternalID: "todoist:6X7rfEVP8hvv25ZU", Project: "Inbox", Title: "Orphan sub-task", Completed: true,
+				},
+			},
+			skipped: []Skipped{
+				{Ref: "task 6X7rfEVP8hvv25ZS", Reason: "deleted"},
+				{Ref: "task 6X7rfEVP8hvv25ZT", Reason: `project "Old stuff" is archived`},
+			},
+		},
+		{
+			name: "todoist rest array", source: Todoist, fixture: "todoist_rest.json",
+			records: []want{
+				{
+					ExternalID: "todoist:2995104339", Title: "Call mom", Priority: models.PriorityMedium,
+					Due: in(2024, 6, 1, 0), Tags: []string{"family"},
+					Items: []models.TodoItem{item("Buy flowers", false)},
+				},
+				{
+					ExternalID: "todoist:2995104340", Title: "Pay rent", Priority: models.PriorityLow,
+					Completed: true, Due: in(2024, 6, 1, 10),
+				},
+			},
+		},
+		{
+			name: "todoist template csv", source: Todoist, fixture: "todoist_template.csv", file: "uploads/Home Renovation.csv",
+			records: []want{
+				{
+					Project: "Home Renovation", Title: "Paint walls", Description: "Two coats\n\nUse the blue tape",
+					Priority: models.PriorityHigh, Due: in(2024, 7, 1, 0), Tags: []string{"diy", "weekend"},
+					Items: []models.TodoItem{item("Buy brushes", false)},
+				},
+				{
+					Project: "Home Renovation", Title: "Fix sink, finally", Priority: models.PriorityMedium,
+					Warnings: []string{`due date "next week" not understood`},
+				},
+			},
+			skipped: []Skipped{
+				{Ref: "line 2", Reason: `section "Kitchen": sections are not supported, its tasks are imported into the project`},
+				{Ref: "line 8", Reason: `unknown row type "bogus"`},
+			},
+		},
+		{
+			name: "trello board", source: Trello, fixture: "trello_board.json",
+			records: []want{
+				{
+					ExternalID: "trello:C1", Project: "Product launch", Title: "Book venue",
+					Completed: true, Tags: []string{"Done"},
+				},
+				{
+					ExternalID: "trello:C2", Project: "Product launch", Title: "Write press release",
+					Description: "Draft **v1**", Priority: models.PriorityHigh, Due: utc(2024, 8, 1, 12),
+					// A lower priority label than one already seen is kept as a tag.
+					Tags:     []string{"To Do", "green", "low"},
+					Items:    []models.TodoItem{item("Outline", true), item("Marketing", true), item("Legal", false)},
+					Warnings: []string{"2 attachment(s) not imported"},
+				},
+				{
+					ExternalID: "trello:C5", Project: "Product launch", Title: "Bad due", Tags: []string{"To Do"},
+					Warnings: []string{`due date "soon" not understood`},
+				},
+			},
+			skipped: []Skipped{
+				{Ref: `card "Old idea"`, Reason: "archived"},
+				{Ref: `card "Someday"`, Reason: `list "Icebox" is archived`},
+			},
+		},
+		{
+			name: "microsoft to do", source: MSToDo, fixture: "mstodo_lists.json",
+			records: []want{
+				{
+					ExternalID: "mstodo:AAMkADAwATM0MTk=", Project: "Tasks", Title: "Renew passport",
+					Description: "Bring photos & form\nLine 2", Priority: models.PriorityHigh, Completed: true,
+					Due: utc(2024, 9, 1, 0), Tags: []string{"Red category", "Travel"},
+					Items: []models.TodoItem{item("Photos", true), item("Form", false)},
+				},
+				{
+					ExternalID: "mstodo:AAMkADAwATM0MTl=", Project: "Tasks", Title: "Water plants",
+					Description: "text body", Priority: models.PriorityMedium, Due: in(2024, 9, 2, 8),
+					Warnings: []string{
+						`time zone "Pacific Standard Time" unknown, due date read in Asia/Jakarta`,
+						"recurrence not imported",
+						"attachments not imported",
+					},
+				},
+			},
+			skipped: []Skipped{{Ref: `list "Empty"`, Reason: "no tasks"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := tt.file
+			if file == "" {
+				file = tt.fixture
+			}
+			res, err := Parse(tt.source, file, data, jakarta)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(res.Records) != len(tt.records) {
+				for _, r := range res.Records {
+					t.Logf("got %q", r.Todo.Title)
+				}
+				t.Fatalf("got %d records, want %d", len(res.Records), len(tt.records))
+			}
+			for i, w := range tt.records {
+				checkRecord(t, i+1, res.Records[i], w)
+			}
+			if !reflect.DeepEqual(res.Skipped, tt.skipped) {
+				t.Errorf("Skipped = %+v, want %+v", res.Skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func checkRecord(t *testing.T, row int, got todoio.Record, w want) {
+	t.Helper()
+	if got.Row != row {
+		t.Errorf("record %d: Row = %d", row, got.Row)
+	}
+	if got.Err != nil {
+		t.Errorf("record %d: %v", row, got.Err)
+	}
+	if got.ExternalID != w.ExternalID || got.Project != w.Project {
+		t.Errorf("record %d: ExternalID, Project = %q, %q; want %q, %q", row, got.ExternalID, got.Project, w.ExternalID, w.Project)
+	}
+	td := got.Todo
+	if td.Title != w.Title || td.Description != w.Description {
+		t.Errorf("record %d: Title, Description = %q, %q; want %q, %q", row, td.Title, td.Description, w.Title, w.Description)
+	}
+	if td.Priority != w.Priority || td.Completed != w.Completed {
+		t.Errorf("record %d: Priority, Completed = %q, %v; want %q, %v", row, td.Priority, td.Completed, w.Priority, w.Completed)
+	}
+	switch {
+	case w.Due.IsZero() && td.DueDate != nil:
+		t.Errorf("record %d: DueDate = %v, want none", row, *td.DueDate)
+	case !w.Due.IsZero() && (td.DueDate == nil || !td.DueDate.Equal(w.Due)):
+		t.Errorf("record %d: DueDate = %v, want %v", row, td.DueDate, w.Due)
+	}
+	if (len(got.Tags) > 0 || len(w.Tags) > 0) && !reflect.DeepEqual(got.Tags, w.Tags) {
+		t.Errorf("record %d: Tags = %q, want %q", row, got.Tags, w.Tags)
+	}
+	if (len(td.Items) > 0 || len(w.Items) > 0) && !reflect.DeepEqual(td.Items, w.Items) {
+		t.Errorf("record %d: Items = %+v, want %+v", row, td.Items, w.Items)
+	}
+	if len(got.Warnings) != len(w.Warnings) {
+		t.Errorf("record %d: Warnings = %q, want %q", row, got.Warnings, w.Warnings)
+		return
+	}
+	for i := range w.Warnings {
+		if !strings.Contains(got.Warnings[i], w.Warnings[i]) {
+			t.Errorf("record %d: warning %d = %q, want %q", row, i, got.Warnings[i], w.Warnings[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source Source
+		name   string
+		data   string
+	}{
+		{Todoist, "export.json", ""},
+		{Todoist, "export.json", "{"},
+		{Todoist, "export.json", `[{"id": true}]`},
+		{Todoist, "project.csv", "CONTENT,PRIORITY\nx,1\n"},
+		{Todoist, "project.csv", "TYPE,PRIORITY\ntask,1\n"},
+		{Todoist, "project.csv", "TYPE,CONTENT\ntask,\"unterminated\n"},
+		{Trello, "board.json", "{}"},
+		{Trello, "board.json", "[]"},
+		{MSToDo, "lists.json", "{}"},
+		{MSToDo, "lists.json", `{"value": 1}`},
+		{Source("asana"), "x.json", "{}"},
+	}
+	for _, tt := range tests {
+		if res, err := Parse(tt.source, tt.name, []byte(tt.data), time.UTC); err == nil {
+			t.Errorf("Parse(%s, %q) = %d records, want error", tt.source, tt.data, len(res.Records))
+		}
+	}
+}
+
+func TestLongValuesAreClipped(t *testing.T) {
+	title := strings.Repeat("é", 150) // 300 bytes
+	data := `[{"id": "1", "content": "` + title + `", "labels": ["` + strings.Repeat("l", 60) + `"]}]`
+	res, err := Parse(Todoist, "export.json", []byte(data), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := res.Records[0]
+	if got := rec.Todo.Title; len(got) > maxTitle || !utf8.ValidString(got) || got != strings.Repeat("é", maxTitle/2) {
+		t.Errorf("Title = %q (%d bytes)", got, len(got))
+	}
+	if len(rec.Tags) != 1 || len(rec.Tags[0]) != maxTag {
+		t.Errorf("Tags = %q", rec.Tags)
+	}
+	if len(rec.Warnings) != 2 {
+		t.Errorf("Warnings = %q, want one per clipped field", rec.Warnings)
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	for in, want := range map[string]Source{"todoist": Todoist, "Trello": Trello, "MSTODO": MSToDo} {
+		if got, err := ParseSource(in); err != nil || got != want {
+			t.Errorf("ParseSource(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseSource("asana"); err == nil {
+		t.Error("ParseSource accepted an unknown source")
+	}
+}
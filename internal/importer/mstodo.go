@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// msToDoList is a task list as returned by Microsoft Graph
+// (GET /me/todo/lists?$expand=tasks, with checklistItems expanded).
+type msToDoList struct {
+	DisplayName string       `json:"displayName"`
+	Tasks       []msToDoTask `json:"tasks"`
+}
+
+type msToDoTask struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Body  struct {
+		Content     string `json:"content"`
+		ContentType string `json:"contentType"`
+	} `json:"body"`
+	Importance  string        `json:"importance"`
+	Status      string        `json:"status"`
+	DueDateTime *msDateTime   `json:"dueDateTime"`
+	Categories  []string      `json:"categories"`
+	Recurrence  *struct{}     `json:"recurrence"`
+	Attachments bool          `json:"hasAttachments"`
+	Checklist   []msCheckItem `json:"checklistItems"`
+}
+
+type msDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+type msCheckItem struct {
+	DisplayName string `json:"displayName"`
+	IsChecked   bool   `json:"isChecked"`
+}
+
+// parseMSToDo accepts {"lists": [...]}, a Graph collection
+// {"value": [...]} of lists, or a single list, each with its tasks.
+func parseMSToDo(data []byte, loc *time.Location) (*Result, error) {
+	var doc struct {
+		msToDoList
+		Lists []msToDoList `json:"lists"`
+		Value []msToDoList `json:"value"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &doc); err != nil {
+		return nil, fmt.Errorf("invalid Microsoft To Do JSON: %w", err)
+	}
+	lists := append(doc.Lists, doc.Value...)
+	if doc.DisplayName != "" || len(doc.Tasks) > 0 {
+		lists = append(lists, doc.msToDoList)
+	}
+	if len(lists) == 0 {
+		return nil, errors.New("invalid Microsoft To Do JSON: no lists")
+	}
+
+	res := &Result{}
+	for _, list := range lists {
+		if len(list.Tasks) == 0 {
+			res.skip(fmt.Sprintf("list %q", list.DisplayName), "no tasks")
+			continue
+		}
+		for _, mt := range list.Tasks {
+			t := newTask(MSToDo, mt.ID, mt.Title, list.DisplayName)
+			if strings.EqualFold(mt.Body.ContentType, "html") {
+				t.describe(plainText(mt.Body.Content))
+			} else {
+				t.describe(mt.Body.Content)
+			}
+			switch strings.ToLower(mt.Importance) {
+			case "high":
+				t.Todo.Priority = models.PriorityHigh
+			case "low":
+				t.Todo.Priority = models.PriorityLow
+			case "normal":
+				t.Todo.Priority = models.PriorityMedium
+			}
+			t.Todo.Completed = strings.EqualFold(mt.Status, "completed")
+			for _, c := range mt.Categories {
+				t.tag(c)
+			}
+			if mt.DueDateTime != nil && mt.DueDateTime.DateTime != "" {
+				msDue(t, mt.DueDateTime, loc)
+			}
+			for _, it := range mt.Checklist {
+				t.item(it.DisplayName, it.IsChecked)
+			}
+			if mt.Recurrence != nil {
+				t.warn("recurrence not imported")
+			}
+			if mt.Attachments {
+				t.warn("attachments not imported")
+			}
+			if err := res.add(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// msDue reads a Graph dateTimeTimeZone. Zones Go does not know (Windows
+// names) fall back to loc.
+func msDue(t *task, dt *msDateTime, loc *time.Location) {
+	zone := loc
+	if dt.TimeZone != "" {
+		if z, err := time.LoadLocation(dt.TimeZone); err == nil {
+			zone = z
+		} else {
+			t.warn("time zone %q unknown, due date read in %s", dt.TimeZone, loc)
+		}
+	}
+	due, ok := parseDate(dt.DateTime, zone)
+	if !ok {
+		t.warn("due date %q not understood", dt.DateTime)
+		return
+	}
+	t.Todo.DueDate = &due
+}
@@ -0,0 +1,303 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// todoistExport is a Sync API dump (projects, items, labels). A plain
+// array of REST API tasks is accepted too.
+type todoistExport struct {
+	Projects []todoistProject `json:"projects"`
+	Items    []todoistItem    `json:"items"`
+	Labels   []struct {
+		ID   flexID `json:"id"`
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type todoistProject struct {
+	ID         flexID `json:"id"`
+	Name       string `json:"name"`
+	IsArchived bool   `json:"is_archived"`
+	IsDeleted  bool   `json:"is_deleted"`
+}
+
+type todoistItem struct {
+	ID          flexID   `json:"id"`
+	ProjectID   flexID   `json:"project_id"`
+	ParentID    flexID   `json:"parent_id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority"`
+	Labels      []flexID `json:"labels"`
+	Checked     bool     `json:"checked"`
+	IsCompleted bool     `json:"is_completed"`
+	IsDeleted   bool     `json:"is_deleted"`
+	Due         *struct {
+		Date        string `json:"date"`
+		Datetime    string `json:"datetime"`
+		String      string `json:"string"`
+		IsRecurring bool   `json:"is_recurring"`
+	} `json:"due"`
+}
+
+// todoistPriority maps API priorities, where 4 is the red p1 and 1 the
+// unmarked default.
+func todoistPriority(p int) models.Priority {
+	switch p {
+	case 4:
+		return models.PriorityHigh
+	case 3:
+		return models.PriorityMedium
+	case 2:
+		return models.PriorityLow
+	}
+	return ""
+}
+
+func parseTodoist(name string, data []byte, loc *time.Location) (*Result, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty export")
+	}
+	if trimmed[0] != '{' && trimmed[0] != '[' {
+		project := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		if project == "" || project == "." {
+			project = "Todoist"
+		}
+		return parseTodoistCSV(project, data, loc)
+	}
+
+	var export todoistExport
+	if trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &export.Items); err != nil {
+			return nil, fmt.Errorf("invalid Todoist JSON: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &export); err != nil {
+		return nil, fmt.Errorf("invalid Todoist JSON: %w", err)
+	}
+
+	projects := map[flexID]todoistProject{}
+	for _, p := range export.Projects {
+		projects[p.ID] = p
+	}
+	labels := map[flexID]string{}
+	for _, l := range export.Labels {
+		labels[l.ID] = l.Name
+	}
+	items := map[flexID]*todoistItem{}
+	for i := range export.Items {
+		items[export.Items[i].ID] = &export.Items[i]
+	}
+	// root follows parent links to the top-level task; sub-tasks at any
+	// depth become checklist items of it.
+	root := func(it *todoistItem) *todoistItem {
+		for depth := 0; it.ParentID != "" && depth < 100; depth++ {
+			parent, ok := items[it.ParentID]
+			if !ok {
+				break
+			}
+			it = parent
+		}
+		return it
+	}
+
+	res := &Result{}
+	tasks := map[flexID]*task{}
+	var order []*task
+	for i := range export.Items {
+		it := &export.Items[i]
+		ref := "task " + string(it.ID)
+		if it.IsDeleted {
+			res.skip(ref, "deleted")
+			continue
+		}
+		if p, ok := projects[it.ProjectID]; ok && (p.IsDeleted || p.IsArchived) {
+			res.skip(ref, "project %q is archived", p.Name)
+			continue
+		}
+		if it.ParentID != "" {
+			if _, ok := items[it.ParentID]; ok {
+				continue
+			}
+		}
+		project := projects[it.ProjectID].Name
+		t := newTask(Todoist, string(it.ID), it.Content, project)
+		t.describe(it.Description)
+		t.Todo.Priority = todoistPriority(it.Priority)
+		t.Todo.Completed = it.Checked || it.IsCompleted
+		for _, l := range it.Labels {
+			if name, ok := labels[l]; ok {
+				t.tag(name)
+			} else {
+				t.tag(string(l))
+			}
+		}
+		if it.Due != nil {
+			todoistDue(t, it.Due.Datetime, it.Due.Date, it.Due.String, it.Due.IsRecurring, loc)
+		}
+		tasks[it.ID] = t
+		order = append(order, t)
+	}
+	for i := range export.Items {
+		it := &export.Items[i]
+		if it.ParentID == "" || it.IsDeleted {
+			continue
+		}
+		if _, ok := items[it.ParentID]; !ok {
+			continue
+		}
+		parent, ok := tasks[root(it).ID]
+		if !ok {
+			continue
+		}
+		parent.item(it.Content, it.Checked || it.IsCompleted)
+		if it.Due != nil || it.Description != "" || len(it.Labels) > 0 {
+			parent.warn("sub-task %q: only its title and state were kept", it.Content)
+		}
+	}
+	for _, t := range order {
+		if err := res.add(t); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func todoistDue(t *task, datetime, date, text string, recurring bool, loc *time.Location) {
+	value := datetime
+	if value == "" {
+		value = date
+	}
+	if due, ok := parseDate(value, loc); ok {
+		t.Todo.DueDate = &due
+	} else if value != "" || text != "" {
+		t.warn("due date %q not understood", firstNonEmpty(value, text))
+	}
+	if recurring {
+		t.warn("recurrence %q not imported", text)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseTodoistCSV reads a project template export: rows of TYPE (task,
+// section, note), CONTENT with inline @labels, DESCRIPTION, PRIORITY (1
+// is p1), INDENT and DATE.
+func parseTodoistCSV(project string, data []byte, loc *time.Location) (*Result, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Todoist CSV: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToUpper(strings.TrimSpace(h))] = i
+	}
+	if _, ok := col["TYPE"]; !ok {
+		return nil, errors.New("invalid Todoist CSV: missing TYPE column")
+	}
+	if _, ok := col["CONTENT"]; !ok {
+		return nil, errors.New("invalid Todoist CSV: missing CONTENT column")
+	}
+
+	res := &Result{}
+	var order []*task
+	var current *task
+	for line := 2; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid Todoist CSV: %w", err)
+		}
+		get := func(name string) string {
+			if i, ok := col[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		ref := fmt.Sprintf("line %d", line)
+		switch strings.ToLower(get("TYPE")) {
+		case "":
+			continue
+		case "section":
+			res.skip(ref, "section %q: sections are not supported, its tasks are imported into the project", get("CONTENT"))
+			continue
+		case "note":
+			if current == nil {
+				res.skip(ref, "note without a task")
+				continue
+			}
+			current.describe(strings.TrimSpace(current.Todo.Description + "\n\n" + get("CONTENT")))
+			continue
+		case "task":
+		default:
+			res.skip(ref, "unknown row type %q", get("TYPE"))
+			continue
+		}
+
+		title, labels := splitLabels(get("CONTENT"))
+		if indent, _ := strconv.Atoi(get("INDENT")); indent > 1 && current != nil {
+			current.item(title, false)
+			continue
+		}
+		t := newTask(Todoist, "", title, project)
+		t.describe(get("DESCRIPTION"))
+		// The template numbers priorities as shown in the app: 1 is p1.
+		if p, err := strconv.Atoi(get("PRIORITY")); err == nil && p >= 1 && p <= 4 {
+			t.Todo.Priority = todoistPriority(5 - p)
+		}
+		for _, l := range labels {
+			t.tag(l)
+		}
+		if date := get("DATE"); date != "" {
+			if due, ok := parseDate(date, loc); ok {
+				t.Todo.DueDate = &due
+			} else {
+				t.warn("due date %q not understood", date)
+			}
+		}
+		current = t
+		order = append(order, t)
+	}
+	for _, t := range order {
+		if err := res.add(t); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// splitLabels removes inline @labels from a task's content.
+func splitLabels(content string) (string, []string) {
+	var words, labels []string
+	for _, w := range strings.Fields(content) {
+		if len(w) > 1 && w[0] == '@' {
+			labels = append(labels, w[1:])
+			continue
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " "), labels
+}
@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// trelloBoard is a board exported as JSON (Menu → Print, export and
+// share → Export as JSON).
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string        `json:"id"`
+		Name        string        `json:"name"`
+		Desc        string        `json:"desc"`
+		Closed      bool          `json:"closed"`
+		IDList      string        `json:"idList"`
+		Due         string        `json:"due"`
+		DueComplete bool          `json:"dueComplete"`
+		Pos         float64       `json:"pos"`
+		Labels      []trelloLabel `json:"labels"`
+		Attachments []struct{}    `json:"attachments"`
+	} `json:"cards"`
+	Checklists []struct {
+		ID         string  `json:"id"`
+		IDCard     string  `json:"idCard"`
+		Name       string  `json:"name"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+type trelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// parseTrello imports one board as a project. Each card's list becomes a
+// tag, so board columns survive; labels that name a priority set it
+// instead of becoming tags.
+func parseTrello(data []byte, loc *time.Location) (*Result, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("invalid Trello JSON: %w", err)
+	}
+	if board.Name == "" && len(board.Cards) == 0 {
+		return nil, fmt.Errorf("invalid Trello JSON: not a board export")
+	}
+
+	listNames := map[string]string{}
+	closedLists := map[string]bool{}
+	for _, l := range board.Lists {
+		listNames[l.ID] = l.Name
+		closedLists[l.ID] = l.Closed
+	}
+	checklists := board.Checklists
+	sort.SliceStable(checklists, func(i, j int) bool { return checklists[i].Pos < checklists[j].Pos })
+
+	cards := board.Cards
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+	res := &Result{}
+	for _, card := range cards {
+		ref := fmt.Sprintf("card %q", card.Name)
+		if card.Closed {
+			res.skip(ref, "archived")
+			continue
+		}
+		if closedLists[card.IDList] {
+			res.skip(ref, "list %q is archived", listNames[card.IDList])
+			continue
+		}
+		t := newTask(Trello, card.ID, card.Name, board.Name)
+		t.describe(card.Desc)
+		t.Todo.Completed = card.DueComplete
+		t.tag(listNames[card.IDList])
+		for _, l := range card.Labels {
+			if p, ok := priorityOfName(l.Name); ok && (t.Todo.Priority == "" || rank(p) > rank(t.Todo.Priority)) {
+				t.Todo.Priority = p
+				continue
+			}
+			if l.Name == "" {
+				t.tag(l.Color)
+			} else {
+				t.tag(l.Name)
+			}
+		}
+		if card.Due != "" {
+			if due, ok := parseDate(card.Due, loc); ok {
+				t.Todo.DueDate = &due
+			} else {
+				t.warn("due date %q not understood", card.Due)
+			}
+		}
+		for _, cl := range checklists {
+			if cl.IDCard != card.ID {
+				continue
+			}
+			items := cl.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, it := range items {
+				t.item(it.Name, it.State == "complete")
+			}
+		}
+		if n := len(card.Attachments); n > 0 {
+			t.warn("%d attachment(s) not imported", n)
+		}
+		if err := res.add(t); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func rank(p models.Priority) int {
+	switch p {
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityMedium:
+		return 2
+	case models.PriorityLow:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+// Package jobs runs background work queued in Postgres.
+//
+// A job is a row in the jobs table with a type and a JSON payload. Code
+// enqueues jobs through a JobRepository, which may be bound to a
+// transaction so the job only exists if the surrounding write commits.
+// Workers in every app instance claim due jobs with FOR UPDATE SKIP
+// LOCKED and run the handler registered for their type, at most
+// Concurrency at a time per type and instance. A failing job is retried
+// with exponential backoff until it runs out of attempts; a job left
+// running by a worker that died is picked up again once its lock is stale.
+// Handlers may therefore run more than once and must be idempotent.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// DefaultMaxAttempts is how often a job runs before it fails for good
+// unless New's caller sets MaxAttempts.
+const DefaultMaxAttempts = 5
+
+// New builds a job of typ with args encoded as its payload, due now.
+// Callers may adjust RunAt, UniqueKey and MaxAttempts before enqueueing.
+func New(typ string, args interface{}) (*models.Job, error) {
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: encode %s: %w", typ, err)
+	}
+	return &models.Job{
+		Type:        typ,
+		Payload:     payload,
+		Status:      models.JobQueued,
+		RunAt:       time.Now(),
+		MaxAttempts: DefaultMaxAttempts,
+	}, nil
+}
+
+// Enqueue builds a job with New and stores it. With a unique key, a job
+// already enqueued under that key is returned instead of a new one.
+func Enqueue(r repository.JobRepository, typ string, args interface{}, uniqueKey string) (*models.Job, error) {
+	job, err := New(typ, args)
+	if err != nil {
+		return nil, err
+	}
+	if uniqueKey != "" {
+		job.UniqueKey = &uniqueKey
+	}
+	if _, err := r.Enqueue(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Handler runs one attempt of a job. Returning an error schedules a retry.
+type Handler func(ctx context.Context, job *models.Job) error
+
+// Options tune how a job type runs.
+type Options struct {
+	// Concurrency caps how many jobs of the type one instance runs at
+	// once. Zero means one.
+	Concurrency int
+}
+
+// Handle registers fn for jobs of typ, decoding each payload into T.
+func Handle[T any](w *Worker, typ string, opts Options, fn func(ctx context.Context, args T) error) {
+	w.Register(typ, opts, func(ctx context.Context, job *models.Job) error {
+		var args T
+		if err := json.Unmarshal(job.Payload, &args); err != nil {
+			return fmt.Errorf("decode payload: %w", err)
+		}
+		return fn(ctx, args)
+	})
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/notify"
+)
+
+// TypeNotify sends a message through a notification channel.
+const TypeNotify = "notify.send"
+
+// NotifyArgs is the payload of a TypeNotify job.
+type NotifyArgs struct {
+	Channel string         `json:"channel"`
+	Message notify.Message `json:"message"`
+}
+
+// Notify is the TypeNotify handler.
+func Notify(dispatcher *notify.Dispatcher) func(ctx context.Context, args NotifyArgs) error {
+	return func(ctx context.Context, args NotifyArgs) error {
+		return dispatcher.Send(ctx, args.Channel, args.Message)
+	}
+}
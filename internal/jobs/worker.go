@@ -0,0 +1,232 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// Retry delays grow from baseDelay, doubling per failed attempt up to
+// maxDelay.
+const (
+	baseDelay = 15 * time.Second
+	maxDelay  = time.Hour
+)
+
+type registration struct {
+	handle Handler
+	slots  chan struct{}
+}
+
+// Worker claims and runs jobs of the registered types.
+type Worker struct {
+	repo     repository.JobRepository
+	interval time.Duration
+	stale    time.Duration
+	drain    time.Duration
+
+	mu       sync.Mutex
+	handlers map[string]*registration
+	wake     chan struct{}
+}
+
+// NewWorker polls for due jobs every interval. A running job's lease is
+// renewed every third of stale; jobs whose lease is older than stale are
+// assumed abandoned. On shutdown, running jobs get drain to finish before
+// their context is cancelled.
+func NewWorker(repo repository.JobRepository, interval, stale, drain time.Duration) *Worker {
+	return &Worker{
+		repo:     repo,
+		interval: interval,
+		stale:    stale,
+		drain:    drain,
+		handlers: map[string]*registration{},
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler of a job type. Register handlers before Run.
+func (w *Worker) Register(typ string, opts Options, h Handler) {
+	n := opts.Concurrency
+	if n <= 0 {
+		n = 1
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[typ] = &registration{handle: h, slots: make(chan struct{}, n)}
+}
+
+// Run claims jobs until ctx is cancelled, then stops claiming and waits
+// for running jobs to finish, at most the drain timeout.
+func (w *Worker) Run(ctx context.Context) {
+	// Jobs outlive ctx so that shutdown lets them finish.
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	var running sync.WaitGroup
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if n, err := w.repo.ReclaimStale(time.Now().Add(-w.stale)); err != nil {
+			log.Printf("jobs: reclaim: %v", err)
+		} else if n > 0 {
+			log.Printf("jobs: requeued %d abandoned job(s)", n)
+		}
+		w.fill(jobCtx, &running)
+		select {
+		case <-ctx.Done():
+			w.wait(&running, cancel)
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+func (w *Worker) wait(running *sync.WaitGroup, cancel context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(w.drain):
+		log.Printf("jobs: cancelling jobs still running after %s", w.drain)
+		cancel()
+		<-done
+	}
+}
+
+// fill claims due jobs while some registered type has a free slot.
+func (w *Worker) fill(ctx context.Context, running *sync.WaitGroup) {
+	for ctx.Err() == nil {
+		types := w.free()
+		if len(types) == 0 {
+			return
+		}
+		job, err := w.repo.Claim(types, time.Now())
+		if err != nil {
+			log.Printf("jobs: claim: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		reg := w.handlers[job.Type]
+		reg.slots <- struct{}{}
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			w.run(ctx, reg, job)
+			<-reg.slots
+			w.signal()
+		}()
+	}
+}
+
+// free lists the types with a free slot. Only fill takes slots, so a
+// listed type still has one when its job is claimed.
+func (w *Worker) free() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []string
+	for typ, reg := range w.handlers {
+		if len(reg.slots) < cap(reg.slots) {
+			out = append(out, typ)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (w *Worker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Worker) run(ctx context.Context, reg *registration, job *models.Job) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		w.heartbeat(ctx, cancel, job)
+	}()
+	err := call(ctx, reg.handle, job)
+	cancel()
+	<-stopped
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.Status, job.LastError, job.FinishedAt = models.JobSucceeded, "", &now
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("jobs: %s job %d failed: %v", job.Type, job.ID, err)
+		job.Status, job.LastError, job.FinishedAt = models.JobFailed, err.Error(), &now
+	default:
+		job.Status, job.LastError, job.RunAt = models.JobQueued, err.Error(), now.Add(retryDelay(job.Attempts))
+	}
+	if err := w.repo.Finish(job); err != nil {
+		log.Printf("jobs: finish job %d: %v", job.ID, err)
+	}
+}
+
+// heartbeat renews the job's lease until ctx is done, so a job that runs
+// longer than the stale timeout is not reclaimed and run a second time.
+// If the lease is lost anyway, the job is cancelled: another worker may
+// already be running it.
+func (w *Worker) heartbeat(ctx context.Context, cancel context.CancelFunc, job *models.Job) {
+	every := w.stale / 3
+	if every < time.Second {
+		every = time.Second
+	}
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := w.repo.Touch(job, time.Now())
+		if errors.Is(err, repository.ErrJobLeaseLost) {
+			log.Printf("jobs: %s job %d lost its lease, cancelling it", job.Type, job.ID)
+			cancel()
+			return
+		}
+		if err != nil {
+			log.Printf("jobs: renew lease of job %d: %v", job.ID, err)
+		}
+	}
+}
+
+// call runs h, turning a panic into an error so one bad job cannot take
+// the worker down.
+func call(ctx context.Context, h Handler, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, job)
+}
+
+func retryDelay(attempts int) time.Duration {
+	if attempts >= 20 {
+		return maxDelay
+	}
+	if d := baseDelay << (attempts - 1); d < maxDelay {
+		return d
+	}
+	return maxDelay
+}
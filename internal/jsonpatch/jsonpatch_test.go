@@ -0,0 +1,223 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// jsonEqual compares two JSON texts structurally.
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result is not JSON: %s", got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("bad expectation %s", want)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+// RFC 6902, Appendix A, plus pointer escapes and array edge cases.
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"A.1 add object member", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`,
+			`{"baz":"qux","foo":"bar"}`},
+		{"A.2 add array element", `{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			`{"foo":["bar","qux","baz"]}`},
+		{"A.3 remove object member", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`,
+			`{"foo":"bar"}`},
+		{"A.4 remove array element", `{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`,
+			`{"foo":["bar","baz"]}`},
+		{"A.5 replace", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`,
+			`{"baz":"boo","foo":"bar"}`},
+		{"A.6 move value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"A.7 move array element", `{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`},
+		{"A.8 test success", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{"A.10 add nested member object", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			`{"foo":"bar","child":{"grandchild":{}}}`},
+		{"A.11 ignore unrecognized elements", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			`{"foo":"bar","baz":"qux"}`},
+		{"A.14 ~ escape ordering", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10}]`,
+			`{"/":9,"~1":10}`},
+		{"A.16 add array value", `{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			`{"foo":["bar",["abc","def"]]}`},
+		{"~1 and ~0 escapes", `{"a/b":1,"m~n":2}`,
+			`[{"op":"test","path":"/a~1b","value":1},{"op":"replace","path":"/m~0n","value":3}]`,
+			`{"a/b":1,"m~n":3}`},
+		{"- appends to the end", `{"tags":["a","b"]}`,
+			`[{"op":"add","path":"/tags/-","value":"c"}]`,
+			`{"tags":["a","b","c"]}`},
+		{"add at array length", `{"tags":["a"]}`,
+			`[{"op":"add","path":"/tags/1","value":"b"}]`,
+			`{"tags":["a","b"]}`},
+		{"add replaces existing member", `{"title":"old"}`,
+			`[{"op":"add","path":"/title","value":"new"}]`,
+			`{"title":"new"}`},
+		{"copy is deep", `{"a":{"b":1}}`,
+			`[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			`{"a":{"b":1},"c":{"b":2}}`},
+		{"replace whole document", `{"a":1}`,
+			`[{"op":"replace","path":"","value":{"b":2}}]`,
+			`{"b":2}`},
+		{"test compares numbers by value", `{"n":1}`,
+			`[{"op":"test","path":"/n","value":1.0}]`,
+			`{"n":1}`},
+		{"test compares objects regardless of order", `{"o":{"a":1,"b":[true,null]}}`,
+			`[{"op":"test","path":"/o","value":{"b":[true,null],"a":1}}]`,
+			`{"o":{"a":1,"b":[true,null]}}`},
+		{"set null", `{"due_date":"2024-01-01T00:00:00Z"}`,
+			`[{"op":"replace","path":"/due_date","value":null}]`,
+			`{"due_date":null}`},
+		{"empty patch", `{"a":1}`, `[]`, `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if !jsonEqual(t, got, tt.want) {
+				t.Fatalf("Apply = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, patch string
+		want             error // nil: any error
+	}{
+		{"A.9 test error", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"bar"}]`, ErrTestFailed},
+		{"A.12 add to nonexistent target", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz/bat","value":"qux"}]`, ErrPath},
+		{"A.15 string is not number", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":"10"}]`, ErrTestFailed},
+		{"test of missing member", `{"a":1}`,
+			`[{"op":"test","path":"/b","value":1}]`, ErrPath},
+		{"test failure rejects earlier ops", `{"a":1}`,
+			`[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":1}]`, ErrTestFailed},
+		{"remove missing member", `{"a":1}`,
+			`[{"op":"remove","path":"/b"}]`, ErrPath},
+		{"replace missing member", `{"a":1}`,
+			`[{"op":"replace","path":"/b","value":1}]`, ErrPath},
+		{"remove - index", `{"a":[1]}`,
+			`[{"op":"remove","path":"/a/-"}]`, ErrPath},
+		{"replace - index", `{"a":[1]}`,
+			`[{"op":"replace","path":"/a/-","value":2}]`, ErrPath},
+		{"index out of range", `{"a":[1]}`,
+			`[{"op":"add","path":"/a/2","value":2}]`, ErrPath},
+		{"leading zero index", `{"a":[1,2]}`,
+			`[{"op":"remove","path":"/a/01"}]`, ErrPath},
+		{"negative index", `{"a":[1,2]}`,
+			`[{"op":"remove","path":"/a/-1"}]`, ErrPath},
+		{"move into own child", `{"a":{"b":{}}}`,
+			`[{"op":"move","from":"/a","path":"/a/b/c"}]`, nil},
+		{"remove whole document", `{"a":1}`,
+			`[{"op":"remove","path":""}]`, nil},
+		{"pointer without slash", `{"a":1}`,
+			`[{"op":"remove","path":"a"}]`, nil},
+		{"missing path", `{"a":1}`,
+			`[{"op":"remove"}]`, nil},
+		{"missing value", `{"a":1}`,
+			`[{"op":"add","path":"/b"}]`, nil},
+		{"missing from", `{"a":1}`,
+			`[{"op":"copy","path":"/b"}]`, nil},
+		{"unknown op", `{"a":1}`,
+			`[{"op":"frobnicate","path":"/a"}]`, nil},
+		{"patch is not an array", `{"a":1}`,
+			`{"op":"remove","path":"/a"}`, nil},
+		{"patch is not JSON", `{"a":1}`, `[{`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatalf("Apply = %s, want error", got)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsLargeNumbers(t *testing.T) {
+	got, err := Apply([]byte(`{"n":12345678901234567890,"f":0.1}`), []byte(`[{"op":"add","path":"/x","value":1}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"f":0.1,"n":12345678901234567890,"x":1}`; string(got) != want {
+		t.Fatalf("Apply = %s, want %s", got, want)
+	}
+}
+
+// RFC 7396, Appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// null deletes only the named member and ignores missing ones.
+		{`{"title":"t","due_date":"2024-01-01T00:00:00Z"}`, `{"due_date":null,"nope":null}`, `{"title":"t"}`},
+		{`{"a":1}`, `{}`, `{"a":1}`},
+	}
+	for _, tt := range tests {
+		got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("MergePatch(%s, %s): %v", tt.doc, tt.patch, err)
+			continue
+		}
+		if !jsonEqual(t, got, tt.want) {
+			t.Errorf("MergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	for _, tt := range []struct{ doc, patch string }{
+		{`{"a":1}`, `{`},
+		{`{"a":1}`, `{"a":2} {"b":3}`},
+		{`{`, `{"a":2}`},
+		{`{"a":1}`, ``},
+	} {
+		if got, err := MergePatch([]byte(tt.doc), []byte(tt.patch)); err == nil {
+			t.Errorf("MergePatch(%s, %s) = %s, want error", tt.doc, tt.patch, got)
+		}
+	}
+}
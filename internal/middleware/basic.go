@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// BasicAuth authenticates HTTP Basic credentials (email and password) for
+// clients that cannot send bearer tokens, such as CalDAV apps. The user is
+// stored like a verified JWT, so GetUserID and GetUserRole work unchanged.
+func BasicAuth(realm string, authenticate func(email, password string) (*models.User, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := c.Get(fiber.HeaderAuthorization)
+		if len(auth) > 6 && strings.EqualFold(auth[:6], "basic ") {
+			raw, err := base64.StdEncoding.DecodeString(auth[6:])
+			if err == nil {
+				if email, password, ok := strings.Cut(string(raw), ":"); ok {
+					if u, err := authenticate(email, password); err == nil {
+						c.Locals("jwt", &jwt.Token{Valid: true, Claims: jwt.MapClaims{
+							"sub":   float64(u.ID),
+							"email": u.Email,
+							"role":  string(u.Role),
+						}})
+						return c.Next()
+					}
+				}
+			}
+		}
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="`+realm+`", charset="UTF-8"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": false, "error": "unauthorized"})
+	}
+}
@@ -21,6 +21,18 @@ func JWT(cfg *config.Config) fiber.Handler {
 	})
 }
 
+// JWTQuery is JWT that also accepts the token as ?access_token=, for
+// clients such as the browser's EventSource that cannot set headers.
+func JWTQuery(cfg *config.Config) fiber.Handler {
+	return jwtware.New(jwtware.Config{
+		SigningKey:   []byte(cfg.JWTSecret),
+		ContextKey:   "jwt",
+		ErrorHandler: jwtError,
+		TokenLookup:  "header:Authorization,cookie:token,query:access_token",
+		AuthScheme:   "Bearer",
+	})
+}
+
 func jwtError(c *fiber.Ctx, err error) error {
 	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": false, "error": "unauthorized"})
 }
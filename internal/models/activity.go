@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TodoActivity records a single field change on a todo.
+type TodoActivity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TodoID    uint      `gorm:"index;not null" json:"todo_id"`
+	ActorID   uint      `gorm:"not null" json:"actor_id"`
+	Field     string    `gorm:"size:50;not null" json:"field"`
+	OldValue  string    `gorm:"type:text" json:"old_value"`
+	NewValue  string    `gorm:"type:text" json:"new_value"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// ActivityEntry is one element of a todo's activity feed: either a comment
+// or a field change.
+type ActivityEntry struct {
+	Type    string        `json:"type"` // "comment" or "change"
+	At      time.Time     `json:"at"`
+	ActorID uint          `json:"actor_id"`
+	Comment *Comment      `json:"comment,omitempty"`
+	Change  *TodoActivity `json:"change,omitempty"`
+}
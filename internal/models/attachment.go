@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Attachment is the metadata of a file uploaded to a todo. The bytes live in
+// the configured blob store under StorageKey.
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TodoID      uint      `gorm:"index;not null" json:"todo_id"`
+	UploaderID  uint      `gorm:"index;not null" json:"uploader_id"`
+	Filename    string    `gorm:"size:255;not null" json:"filename"`
+	ContentType string    `gorm:"size:255;not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	StorageKey  string    `gorm:"size:512;uniqueIndex;not null" json:"-"`
+	URL         string    `gorm:"-" json:"url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Comment bodies are Markdown; they are stored and returned verbatim.
+type Comment struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TodoID    uint       `gorm:"index;not null" json:"todo_id"`
+	AuthorID  uint       `gorm:"index;not null" json:"author_id"`
+	Author    *User      `gorm:"constraint:OnDelete:CASCADE" json:"author,omitempty" validate:"-"`
+	Body      string     `gorm:"type:text;not null" json:"body" validate:"required,max=10000"`
+	Mentions  []uint     `gorm:"-" json:"mentions,omitempty"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobQueued    ImportJobStatus = "queued"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobSucceeded ImportJobStatus = "succeeded"
+	ImportJobFailed    ImportJobStatus = "failed"
+)
+
+// ImportJob is an import of another tool's export running in the
+// background. Payload holds the uploaded file until the job finishes;
+// Report is the outcome, including what was skipped.
+type ImportJob struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	OwnerID    uint            `gorm:"index;not null" json:"owner_id"`
+	Source     string          `gorm:"size:20;not null" json:"source"`
+	FileName   string          `gorm:"size:255" json:"file_name,omitempty"`
+	DryRun     bool            `gorm:"not null;default:false" json:"dry_run"`
+	Status     ImportJobStatus `gorm:"size:20;not null;index" json:"status"`
+	Error      string          `gorm:"type:text" json:"error,omitempty"`
+	Payload    []byte          `json:"-"`
+	Report     json.RawMessage `gorm:"serializer:json;type:jsonb" json:"report,omitempty"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
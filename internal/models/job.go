@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed is a job that failed every attempt; it only runs again
+	// when retried by an admin.
+	JobFailed JobStatus = "failed"
+)
+
+// Job is a unit of background work stored in Postgres. Workers claim
+// queued jobs whose RunAt has passed with FOR UPDATE SKIP LOCKED, so every
+// app instance can run them without Redis. A failed attempt is requeued
+// with backoff until MaxAttempts. UniqueKey, when set, allows only one job
+// with that key to ever be enqueued.
+type Job struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Type        string          `gorm:"size:50;not null;index:idx_jobs_due,priority:2" json:"type"`
+	Payload     json.RawMessage `gorm:"serializer:json;type:jsonb;not null" json:"payload"`
+	Status      JobStatus       `gorm:"size:20;not null;index:idx_jobs_due,priority:1" json:"status"`
+	UniqueKey   *string         `gorm:"size:200;uniqueIndex" json:"unique_key,omitempty"`
+	RunAt       time.Time       `gorm:"not null;index:idx_jobs_due,priority:3" json:"run_at"`
+	Attempts    int             `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int             `gorm:"not null;default:5" json:"max_attempts"`
+	LastError   string          `gorm:"type:text" json:"last_error,omitempty"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationReminder NotificationType = "reminder"
+	NotificationShare    NotificationType = "share"
+	NotificationComment  NotificationType = "comment"
+	NotificationMention  NotificationType = "mention"
+)
+
+type Notification struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"index:idx_notifications_user_read;not null" json:"user_id"`
+	Type      NotificationType `gorm:"size:30;not null" json:"type"`
+	Title     string           `gorm:"size:200;not null" json:"title"`
+	Body      string           `gorm:"type:text" json:"body"`
+	TodoID    *uint            `gorm:"index" json:"todo_id,omitempty"`
+	ReadAt    *time.Time       `gorm:"index:idx_notifications_user_read" json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type NotificationCounts struct {
+	Unread int64 `json:"unread"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Project struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:120;not null" json:"name" validate:"required,min=1,max=120"`
+	OwnerID   uint      `gorm:"index;not null" json:"owner_id"`
+	Todos     []Todo    `gorm:"foreignKey:ProjectID;constraint:OnDelete:SET NULL" json:"-"`
+	Shares    []Share   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type ReminderChannel string
+
+const (
+	ReminderEmail   ReminderChannel = "email"
+	ReminderWebhook ReminderChannel = "webhook"
+	ReminderInApp   ReminderChannel = "inapp"
+)
+
+type Reminder struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	TodoID        uint            `gorm:"index;not null" json:"todo_id"`
+	UserID        uint            `gorm:"index;not null" json:"user_id"`
+	RemindAt      *time.Time      `json:"remind_at,omitempty"`
+	OffsetMinutes *int            `json:"offset_minutes,omitempty" validate:"omitempty,min=0,max=525600"`
+	FireAt        *time.Time      `gorm:"index" json:"fire_at,omitempty"`
+	Channel       ReminderChannel `gorm:"size:20;not null;default:inapp" json:"channel" validate:"oneof=email webhook inapp"`
+	Target        string          `gorm:"size:500" json:"target,omitempty" validate:"omitempty,max=500"`
+	FiredAt       *time.Time      `gorm:"index" json:"fired_at,omitempty"`
+	LastError     string          `gorm:"size:500" json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
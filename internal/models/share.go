@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type Permission string
+
+const (
+	PermissionViewer Permission = "viewer"
+	PermissionEditor Permission = "editor"
+)
+
+// Allows reports whether p grants at least the access level of need.
+func (p Permission) Allows(need Permission) bool {
+	switch p {
+	case PermissionEditor:
+		return true
+	case PermissionViewer:
+		return need == PermissionViewer
+	}
+	return false
+}
+
+// Share grants a collaborator access to either a single todo or a whole
+// project; exactly one of TodoID and ProjectID is set.
+type Share struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TodoID     *uint      `gorm:"uniqueIndex:idx_shares_todo_user" json:"todo_id,omitempty"`
+	ProjectID  *uint      `gorm:"uniqueIndex:idx_shares_project_user" json:"project_id,omitempty"`
+	UserID     uint       `gorm:"uniqueIndex:idx_shares_todo_user;uniqueIndex:idx_shares_project_user;index;not null" json:"user_id"`
+	Permission Permission `gorm:"size:10;not null;default:viewer" json:"permission" validate:"oneof=viewer editor"`
+	CreatedBy  uint       `json:"created_by"`
+	User       *User      `gorm:"constraint:OnDelete:CASCADE" json:"user,omitempty" validate:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
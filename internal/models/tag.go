@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Tag is a label shared by todos. Names are stored lowercased.
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;uniqueIndex;not null" json:"name"`
+	CreatedAt time.Time `json:"-"`
+}
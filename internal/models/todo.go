@@ -11,13 +11,31 @@ const (
 )
 
 type Todo struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	Title       string     `gorm:"size:200;not null" json:"title" validate:"required,min=3,max=200"`
-	Description string     `gorm:"type:text" json:"description" validate:"max=2000"`
-	Completed   bool       `gorm:"default:false" json:"completed"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	Priority    Priority   `gorm:"size:10;default:medium" json:"priority" validate:"oneof=low medium high"`
-	OwnerID     uint       `json:"owner_id"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Title        string         `gorm:"size:200;not null" json:"title" validate:"required,min=3,max=200"`
+	Description  string         `gorm:"type:text" json:"description" validate:"max=2000"`
+	Completed    bool           `gorm:"default:false" json:"completed"`
+	DueDate      *time.Time     `json:"due_date,omitempty"`
+	Priority     Priority       `gorm:"size:10;default:medium" json:"priority" validate:"oneof=low medium high"`
+	OwnerID      uint           `gorm:"uniqueIndex:idx_todos_owner_external" json:"owner_id"`
+	ProjectID    *uint          `gorm:"index" json:"project_id,omitempty"`
+	Shares       []Share        `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+	Comments     []Comment      `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+	Activities   []TodoActivity `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+	Attachments  []Attachment   `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"-"`
+	Tags         []Tag          `gorm:"many2many:todo_tags;constraint:OnDelete:CASCADE" json:"tags,omitempty"`
+	AutoComplete bool           `gorm:"default:false" json:"auto_complete"`
+	Items        []TodoItem     `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	Progress     *Progress      `gorm:"-" json:"progress,omitempty"`
+	Reminders    []Reminder     `gorm:"foreignKey:TodoID;constraint:OnDelete:CASCADE" json:"reminders,omitempty"`
+	RRule        string         `gorm:"size:255" json:"rrule,omitempty"`
+	SeriesID     *uint          `gorm:"index" json:"series_id,omitempty"`
+	Occurrence   int            `gorm:"not null;default:1" json:"occurrence"`
+	Next         *Todo          `gorm:"-" json:"next,omitempty"`
+	ExternalID   *string        `gorm:"size:255;uniqueIndex:idx_todos_owner_external" json:"external_id,omitempty"`
+	Version      uint           `gorm:"not null;default:1" json:"version"`
+	Rank         *float32       `gorm:"->;-:migration" json:"rank,omitempty"`
+	Snippet      string         `gorm:"->;-:migration" json:"snippet,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
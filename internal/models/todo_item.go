@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type TodoItem struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TodoID    uint      `gorm:"index;not null" json:"todo_id"`
+	Title     string    `gorm:"size:200;not null" json:"title" validate:"required,min=1,max=200"`
+	Completed bool      `gorm:"default:false" json:"completed"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Progress summarizes checklist completion, e.g. 3 of 5 items done.
+type Progress struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SavedView is a named combination of todo list parameters. System views
+// (Today, Upcoming, Overdue) are not stored; they have a Key instead of an
+// ID.
+type SavedView struct {
+	ID            uint      `gorm:"primaryKey" json:"id,omitempty"`
+	Key           string    `gorm:"-" json:"key,omitempty"`
+	OwnerID       uint      `gorm:"index;not null" json:"owner_id,omitempty"`
+	Name          string    `gorm:"size:120;not null" json:"name" validate:"required,min=1,max=120"`
+	Query         string    `gorm:"size:200" json:"q" validate:"max=200"`
+	Filter        string    `gorm:"size:1000" json:"filter" validate:"max=1000"`
+	Sort          string    `gorm:"size:20" json:"sort" validate:"omitempty,oneof=created_asc created_desc due_asc due_desc relevance"`
+	ProjectID     *uint     `json:"project_id"`
+	IncludeShared bool      `gorm:"not null;default:false" json:"include_shared"`
+	System        bool      `gorm:"-" json:"system"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types delivered to webhooks.
+const (
+	EventTodoCreated   = "todo.created"
+	EventTodoUpdated   = "todo.updated"
+	EventTodoCompleted = "todo.completed"
+	EventTodoDeleted   = "todo.deleted"
+	EventUserCreated   = "user.created"
+	EventUserUpdated   = "user.updated"
+)
+
+// EventTypes lists every event a webhook can subscribe to.
+var EventTypes = []string{
+	EventTodoCreated, EventTodoUpdated, EventTodoCompleted, EventTodoDeleted,
+	EventUserCreated, EventUserUpdated,
+}
+
+// Webhook is an endpoint that receives the events it subscribes to for
+// its owner's data. Global webhooks, which only admins can register,
+// receive them for every user. Secret signs each delivery.
+type Webhook struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	OwnerID     uint              `gorm:"index;not null" json:"owner_id"`
+	URL         string            `gorm:"size:2048;not null" json:"url" validate:"required,url,max=2048"`
+	Description string            `gorm:"size:200" json:"description" validate:"max=200"`
+	Events      []string          `gorm:"serializer:json;type:jsonb;not null" json:"events" validate:"required,min=1,dive,required"`
+	Global      bool              `gorm:"not null;default:false" json:"global"`
+	Active      bool              `gorm:"not null;default:true" json:"active"`
+	Secret      string            `gorm:"size:100;not null" json:"-"`
+	Deliveries  []WebhookDelivery `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// OutboxEvent is a domain event recorded by a write, waiting for the relay
+// to dispatch it to subscribers. UserID is the user whose data changed.
+// A failed dispatch is retried at NextAttemptAt; ProcessedAt is set once
+// every subscriber handled it or it ran out of attempts.
+type OutboxEvent struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	Type          string          `gorm:"size:50;not null" json:"type"`
+	UserID        uint            `gorm:"index;not null" json:"user_id"`
+	Payload       json.RawMessage `gorm:"serializer:json;type:jsonb;not null" json:"payload"`
+	Attempts      int             `gorm:"not null;default:0" json:"attempts,omitempty"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	LastError     string          `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ProcessedAt   *time.Time      `gorm:"index" json:"processed_at,omitempty"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead is a delivery that failed every attempt; it is only
+	// sent again when redelivered by hand.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event for one webhook. Payload is the exact
+// request body, so a redelivery sends the same bytes.
+type WebhookDelivery struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	WebhookID      uint             `gorm:"index;not null;uniqueIndex:idx_webhook_deliveries_event" json:"webhook_id"`
+	Webhook        *Webhook         `gorm:"-" json:"-"`
+	EventID        uint             `gorm:"index;not null;uniqueIndex:idx_webhook_deliveries_event" json:"event_id"`
+	EventType      string           `gorm:"size:50;not null" json:"event_type"`
+	Payload        json.RawMessage  `gorm:"serializer:json;type:jsonb;not null" json:"payload"`
+	Status         DeliveryStatus   `gorm:"size:20;not null;index:idx_webhook_deliveries_due" json:"status"`
+	Attempts       int              `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time       `gorm:"index:idx_webhook_deliveries_due" json:"next_attempt_at,omitempty"`
+	LastStatusCode int              `json:"last_status_code,omitempty"`
+	LastError      string           `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	Logs           []WebhookAttempt `gorm:"foreignKey:DeliveryID;constraint:OnDelete:CASCADE" json:"attempt_log,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// WebhookAttempt logs one HTTP request of a delivery.
+type WebhookAttempt struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	DeliveryID uint      `gorm:"index;not null" json:"delivery_id"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `gorm:"type:text" json:"error,omitempty"`
+	Response   string    `gorm:"type:text" json:"response,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
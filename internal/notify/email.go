@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type EmailChannel struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	From string
+}
+
+// Send mails msg to the recipient user's own address. msg.Target is ignored
+// so that a user-supplied value can never make the server relay mail to an
+// arbitrary address.
+func (e *EmailChannel) Send(ctx context.Context, msg Message) error {
+	to := msg.Email
+	if to == "" {
+		return errors.New("notify: no email recipient")
+	}
+	var auth smtp.Auth
+	if e.User != "" {
+		auth = smtp.PlainAuth("", e.User, e.Pass, e.Host)
+	}
+	body := strings.Join([]string{
+		"From: " + e.From,
+		"To: " + to,
+		"Subject: " + sanitizeHeader(msg.Title),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(fmt.Sprintf("%s:%d", e.Host, e.Port), auth, e.From, []string{to}, []byte(body))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func sanitizeHeader(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned when a request to a user-supplied URL would
+// connect to a loopback, private, link-local or otherwise internal address.
+var ErrBlockedAddress = errors.New("notify: destination address not allowed")
+
+// maxRedirects caps the redirects followed by NewPublicClient.
+const maxRedirects = 5
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewPublicClient returns an HTTP client for URLs chosen by users, such as
+// webhook targets. Every connection it makes, including those following a
+// redirect, is checked after DNS resolution and refused unless the address
+// is public, so a hostname cannot be pointed at the app's own network.
+// Proxies from the environment are ignored for the same reason.
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   denyInternal,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// CheckHost resolves host and fails with ErrBlockedAddress if any of its
+// addresses is not public.
+func CheckHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrBlockedAddress, host, addr)
+		}
+	}
+	return nil
+}
+
+// denyInternal is the dialer's Control hook. It sees the resolved address
+// being connected to, so DNS answers cannot be swapped after a check.
+func denyInternal(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+	return nil
+}
+
+// checkRedirect refuses redirects to other schemes or internal hosts. The
+// dialer would refuse the connection anyway; checking here names the
+// redirect in the error.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("notify: stopped after %d redirects", maxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("notify: redirect to %s URL refused", req.URL.Scheme)
+	}
+	if err := CheckHost(req.Context(), req.URL.Hostname()); err != nil {
+		return fmt.Errorf("redirect to %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
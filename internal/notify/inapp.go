@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// Inbox stores in-app notifications; NotificationService satisfies it.
+type Inbox interface {
+	Notify(userID uint, kind models.NotificationType, title, body string, todoID *uint) (*models.Notification, error)
+}
+
+// InAppChannel delivers messages to the user's notification inbox.
+type InAppChannel struct {
+	Inbox Inbox
+}
+
+func (c *InAppChannel) Send(_ context.Context, msg Message) error {
+	var todoID *uint
+	if msg.TodoID != 0 {
+		id := msg.TodoID
+		todoID = &id
+	}
+	_, err := c.Inbox.Notify(msg.UserID, models.NotificationType(msg.Kind), msg.Title, msg.Body, todoID)
+	return err
+}
@@ -0,0 +1,51 @@
+// Package notify delivers user-facing messages through pluggable channels
+// such as email, webhooks or the in-app inbox.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Message is a channel-agnostic notification.
+type Message struct {
+	Kind    string // e.g. "reminder"
+	UserID  uint
+	Email   string
+	Title   string
+	Body    string
+	TodoID  uint
+	Target  string // webhook URL; other channels ignore it
+	Payload map[string]interface{}
+}
+
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Dispatcher routes messages to channels registered by name.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	channels map[string]Channel
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{channels: map[string]Channel{}}
+}
+
+func (d *Dispatcher) Register(name string, ch Channel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.channels[name] = ch
+}
+
+func (d *Dispatcher) Send(ctx context.Context, channel string, msg Message) error {
+	d.mu.RLock()
+	ch, ok := d.channels[channel]
+	d.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("notify: channel %q not configured", channel)
+	}
+	return ch.Send(ctx, msg)
+}
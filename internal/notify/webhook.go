@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookChannel POSTs the message as JSON to msg.Target. Targets are
+// user-supplied, so NewWebhookChannel only lets it reach public addresses.
+type WebhookChannel struct {
+	Client *http.Client
+}
+
+func NewWebhookChannel(timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{Client: NewPublicClient(timeout)}
+}
+
+func (w *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	if msg.Target == "" {
+		return errors.New("notify: webhook target required")
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"kind":    msg.Kind,
+		"user_id": msg.UserID,
+		"todo_id": msg.TodoID,
+		"title":   msg.Title,
+		"body":    msg.Body,
+		"data":    msg.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.Target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook responded %s", resp.Status)
+	}
+	return nil
+}
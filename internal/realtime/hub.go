@@ -0,0 +1,160 @@
+// Package realtime wakes up clients streaming their changes when new
+// events are recorded. Every app instance LISTENs on a Postgres channel
+// that a trigger on the outbox table notifies, so a change committed
+// through any instance reaches subscribers on all of them.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Channel is the NOTIFY channel the outbox trigger publishes to; it is
+// spelled out in internal/database/migrations/0018_outbox_notify.up.sql.
+const Channel = "outbox_events"
+
+// Subscription is one client waiting for its user's events. Wake fires
+// (coalesced) when there may be new events; Done is closed when the hub
+// shuts down.
+type Subscription struct {
+	UserID uint
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+func (s *Subscription) Wake() <-chan struct{} { return s.wake }
+func (s *Subscription) Done() <-chan struct{} { return s.done }
+
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[uint]map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: map[uint]map[*Subscription]struct{}{}}
+}
+
+func (h *Hub) Subscribe(userID uint) *Subscription {
+	s := &Subscription{UserID: userID, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(s.done)
+		return s
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[*Subscription]struct{}{}
+	}
+	h.subs[userID][s] = struct{}{}
+	return s
+}
+
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if subs := h.subs[s.UserID]; subs != nil {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(h.subs, s.UserID)
+		}
+	}
+}
+
+// Notify wakes the subscribers of a user.
+func (h *Hub) Notify(userID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs[userID] {
+		wake(s)
+	}
+}
+
+// NotifyAll wakes every subscriber, e.g. after notifications may have
+// been missed while reconnecting.
+func (h *Hub) NotifyAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.subs {
+		for s := range subs {
+			wake(s)
+		}
+	}
+}
+
+func wake(s *Subscription) {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close ends every subscription.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, subs := range h.subs {
+		for s := range subs {
+			close(s.done)
+		}
+	}
+	h.subs = map[uint]map[*Subscription]struct{}{}
+}
+
+// Listen holds a dedicated LISTEN connection until ctx is cancelled,
+// reconnecting with backoff when it drops, then closes the hub.
+func (h *Hub) Listen(ctx context.Context, dsn string) {
+	defer h.Close()
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := h.listen(ctx, dsn)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("realtime: %v; reconnecting in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (h *Hub) listen(ctx context.Context, dsn string) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	// Anything committed while we were not listening is picked up now.
+	h.NotifyAll()
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var payload struct {
+			UserID uint `json:"user_id"`
+		}
+		if err := json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+			log.Printf("realtime: bad notification %q: %v", n.Payload, err)
+			continue
+		}
+		h.Notify(payload.UserID)
+	}
+}
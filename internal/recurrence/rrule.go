@@ -0,0 +1,374 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules used
+// by recurring todos: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY
+// (with ordinals for monthly rules), BYMONTHDAY, UNTIL and COUNT.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxIterations bounds the search for the next occurrence so that rules
+// which can never match (e.g. BYMONTHDAY=31 with FREQ=MONTHLY;INTERVAL=12
+// starting in February) terminate.
+const maxIterations = 1000
+
+// WeekdayNum is a BYDAY entry such as MO, 2TU or -1FR. N is zero when no
+// ordinal is given.
+type WeekdayNum struct {
+	Day time.Weekday
+	N   int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Until      *time.Time
+	// FloatingUntil is set when UNTIL had no "Z": Until then holds a wall
+	// clock time (stored as UTC) that Next reads in the owner's location.
+	FloatingUntil bool
+	Count         int
+}
+
+var dayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var dayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse parses an RRULE value, with or without the leading "RRULE:".
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("rrule: empty rule")
+	}
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("rrule: malformed part %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch f := Frequency(strings.ToUpper(val)); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("rrule: unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rrule: invalid INTERVAL %q", val)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rrule: invalid COUNT %q", val)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, floating, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			r.Until, r.FloatingUntil = &t, floating
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, err := parseWeekdayNum(d)
+				if err != nil {
+					return nil, err
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("rrule: invalid BYMONTHDAY %q", d)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				return nil, errors.New("rrule: only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("rrule: unsupported part %q", key)
+		}
+	}
+	if r.Freq == "" {
+		return nil, errors.New("rrule: FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("rrule: COUNT and UNTIL are mutually exclusive")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly {
+			return nil, errors.New("rrule: BYDAY ordinals are only supported with FREQ=MONTHLY")
+		}
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != Monthly {
+		return nil, errors.New("rrule: BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	if len(r.ByMonthDay) > 0 && len(r.ByDay) > 0 {
+		return nil, errors.New("rrule: BYDAY and BYMONTHDAY cannot be combined")
+	}
+	return r, nil
+}
+
+// parseUntil parses an UNTIL value. Only the UTC form ending in "Z" is an
+// absolute time; a local date-time or a date is floating.
+func parseUntil(v string) (t time.Time, floating bool, err error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, layout != "20060102T150405Z", nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("rrule: invalid UNTIL %q", v)
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("rrule: invalid BYDAY %q", s)
+	}
+	day, ok := dayCodes[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("rrule: invalid BYDAY %q", s)
+	}
+	wd := WeekdayNum{Day: day}
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("rrule: invalid BYDAY %q", s)
+		}
+		wd.N = n
+	}
+	return wd, nil
+}
+
+// String returns the canonical RRULE value (without the "RRULE:" prefix).
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = dayNames[wd.Day]
+			if wd.N != 0 {
+				days[i] = strconv.Itoa(wd.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Until != nil && r.FloatingUntil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+	} else if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after prev. occurrence is the
+// 1-based index of prev within the series and is checked against COUNT.
+// Calendar arithmetic happens in loc so that the wall-clock time of day is
+// kept across DST changes, and a floating UNTIL is read in loc too. ok is
+// false when the series has ended.
+func (r *Rule) Next(prev time.Time, occurrence int, loc *time.Location) (next time.Time, ok bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	p := prev.In(loc)
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(p)
+	case Weekly:
+		next, ok = r.nextWeekly(p)
+	case Monthly:
+		next, ok = r.nextMonthly(p)
+	case Yearly:
+		next, ok = r.nextYearly(p)
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	if until := r.until(loc); until != nil && next.After(*until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// until returns the end of the series as an instant, resolving a floating
+// UNTIL in loc.
+func (r *Rule) until(loc *time.Location) *time.Time {
+	if r.Until == nil || !r.FloatingUntil {
+		return r.Until
+	}
+	u := *r.Until
+	t := time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), 0, loc)
+	return &t
+}
+
+func at(p time.Time, y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, p.Hour(), p.Minute(), p.Second(), 0, p.Location())
+}
+
+func (r *Rule) matchesDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) nextDaily(p time.Time) (time.Time, bool) {
+	for i := 1; i <= maxIterations; i++ {
+		t := at(p, p.Year(), p.Month(), p.Day()+i*r.Interval)
+		if r.matchesDay(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) nextWeekly(p time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return at(p, p.Year(), p.Month(), p.Day()+7*r.Interval), true
+	}
+	// Offsets of the requested weekdays from Monday (WKST=MO).
+	offsets := make([]int, 0, len(r.ByDay))
+	for _, wd := range r.ByDay {
+		offsets = append(offsets, (int(wd.Day)+6)%7)
+	}
+	sort.Ints(offsets)
+	monday := p.Day() - (int(p.Weekday())+6)%7
+	for week := 0; week <= maxIterations; week += r.Interval {
+		for _, off := range offsets {
+			t := at(p, p.Year(), p.Month(), monday+7*week+off)
+			if t.After(p) {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) nextMonthly(p time.Time) (time.Time, bool) {
+	for i := 0; i <= maxIterations; i += r.Interval {
+		first := at(p, p.Year(), p.Month()+time.Month(i), 1)
+		for _, t := range r.monthCandidates(p, first) {
+			if t.After(p) {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthCandidates lists the occurrences within the month starting at first,
+// in chronological order.
+func (r *Rule) monthCandidates(p, first time.Time) []time.Time {
+	y, m := first.Year(), first.Month()
+	days := daysIn(y, m)
+	var out []int
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d = days + d + 1
+			}
+			if d >= 1 && d <= days {
+				out = append(out, d)
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			var matches []int
+			for d := 1; d <= days; d++ {
+				if time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+					matches = append(matches, d)
+				}
+			}
+			switch {
+			case wd.N == 0:
+				out = append(out, matches...)
+			case wd.N > 0 && wd.N <= len(matches):
+				out = append(out, matches[wd.N-1])
+			case wd.N < 0 && -wd.N <= len(matches):
+				out = append(out, matches[len(matches)+wd.N])
+			}
+		}
+	default:
+		// Same day of month as the previous occurrence; months that are
+		// too short are skipped, as RFC 5545 requires.
+		if p.Day() <= days {
+			out = append(out, p.Day())
+		}
+	}
+	sort.Ints(out)
+	ts := make([]time.Time, 0, len(out))
+	for _, d := range out {
+		ts = append(ts, at(p, y, m, d))
+	}
+	return ts
+}
+
+func (r *Rule) nextYearly(p time.Time) (time.Time, bool) {
+	for i := r.Interval; i <= maxIterations; i += r.Interval {
+		y := p.Year() + i
+		if p.Day() <= daysIn(y, p.Month()) {
+			return at(p, y, p.Month(), p.Day()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
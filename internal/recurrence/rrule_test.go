@@ -0,0 +1,159 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestNext(t *testing.T) {
+	utc := time.UTC
+	newYork := mustLoad(t, "America/New_York")
+	berlin := mustLoad(t, "Europe/Berlin")
+	jakarta := mustLoad(t, "Asia/Jakarta")
+	date := func(loc *time.Location, y int, m time.Month, d, h int) time.Time {
+		return time.Date(y, m, d, h, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name       string
+		rule       string
+		prev       time.Time
+		occurrence int
+		loc        *time.Location
+		want       time.Time // zero when the series has ended
+	}{
+		{"daily", "FREQ=DAILY", date(utc, 2024, 1, 1, 9), 1, utc, date(utc, 2024, 1, 2, 9)},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", date(utc, 2024, 1, 1, 9), 1, utc, date(utc, 2024, 1, 4, 9)},
+		{"daily across month end", "FREQ=DAILY", date(utc, 2024, 1, 31, 9), 1, utc, date(utc, 2024, 2, 1, 9)},
+		{"daily on weekdays skips weekend", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", date(utc, 2024, 1, 5, 9), 1, utc, date(utc, 2024, 1, 8, 9)},
+		{"weekly", "FREQ=WEEKLY", date(utc, 2024, 1, 1, 9), 1, utc, date(utc, 2024, 1, 8, 9)},
+		{"weekly byday same week", "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(utc, 2024, 1, 1, 9), 1, utc, date(utc, 2024, 1, 3, 9)},
+		{"weekly byday next week", "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(utc, 2024, 1, 5, 9), 1, utc, date(utc, 2024, 1, 8, 9)},
+		{"weekly byday interval", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH", date(utc, 2024, 1, 4, 9), 1, utc, date(utc, 2024, 1, 16, 9)},
+		{"monthly skips short months", "FREQ=MONTHLY", date(utc, 2024, 1, 31, 9), 1, utc, date(utc, 2024, 3, 31, 9)},
+		{"monthly interval", "FREQ=MONTHLY;INTERVAL=2", date(utc, 2024, 11, 15, 9), 1, utc, date(utc, 2025, 1, 15, 9)},
+		{"monthly last day leap year", "FREQ=MONTHLY;BYMONTHDAY=-1", date(utc, 2024, 1, 31, 9), 1, utc, date(utc, 2024, 2, 29, 9)},
+		{"monthly last day 30-day month", "FREQ=MONTHLY;BYMONTHDAY=-1", date(utc, 2024, 3, 31, 9), 1, utc, date(utc, 2024, 4, 30, 9)},
+		{"monthly bymonthday 31 skips april", "FREQ=MONTHLY;BYMONTHDAY=31", date(utc, 2024, 3, 31, 9), 1, utc, date(utc, 2024, 5, 31, 9)},
+		{"monthly second tuesday", "FREQ=MONTHLY;BYDAY=2TU", date(utc, 2024, 1, 9, 9), 1, utc, date(utc, 2024, 2, 13, 9)},
+		{"monthly last friday", "FREQ=MONTHLY;BYDAY=-1FR", date(utc, 2024, 1, 26, 9), 1, utc, date(utc, 2024, 2, 23, 9)},
+		{"yearly", "FREQ=YEARLY", date(utc, 2024, 6, 1, 9), 1, utc, date(utc, 2025, 6, 1, 9)},
+		{"yearly leap day", "FREQ=YEARLY", date(utc, 2024, 2, 29, 9), 1, utc, date(utc, 2028, 2, 29, 9)},
+		{"count not reached", "FREQ=DAILY;COUNT=3", date(utc, 2024, 1, 2, 9), 2, utc, date(utc, 2024, 1, 3, 9)},
+		{"count reached", "FREQ=DAILY;COUNT=3", date(utc, 2024, 1, 3, 9), 3, utc, time.Time{}},
+		{"until inclusive", "FREQ=DAILY;UNTIL=20240103T090000Z", date(utc, 2024, 1, 2, 9), 1, utc, date(utc, 2024, 1, 3, 9)},
+		{"until passed", "FREQ=DAILY;UNTIL=20240103T090000Z", date(utc, 2024, 1, 3, 9), 2, utc, time.Time{}},
+		{"utc until in other zone", "FREQ=DAILY;UNTIL=20240103T090000Z", date(newYork, 2024, 1, 2, 9), 1, newYork, time.Time{}},
+		{"floating until in owner zone", "FREQ=DAILY;UNTIL=20240103T090000", date(newYork, 2024, 1, 2, 9), 1, newYork, date(newYork, 2024, 1, 3, 9)},
+		{"floating date until in owner zone", "FREQ=DAILY;UNTIL=20240103", date(jakarta, 2024, 1, 3, 6), 1, jakarta, time.Time{}},
+		{"floating date until includes the day", "FREQ=DAILY;UNTIL=20240103", date(jakarta, 2024, 1, 2, 23), 1, jakarta, date(jakarta, 2024, 1, 3, 23)},
+		{"daily into DST keeps wall clock", "FREQ=DAILY", date(newYork, 2024, 3, 9, 9), 1, newYork, date(newYork, 2024, 3, 10, 9)},
+		{"weekly out of DST keeps wall clock", "FREQ=WEEKLY", date(berlin, 2024, 10, 21, 8), 1, berlin, date(berlin, 2024, 10, 28, 8)},
+		{"monthly across DST keeps wall clock", "FREQ=MONTHLY", date(newYork, 2024, 10, 15, 9), 1, newYork, date(newYork, 2024, 11, 15, 9)},
+		{"evaluated in owner zone, not UTC", "FREQ=WEEKLY;BYDAY=MO", time.Date(2024, 1, 7, 23, 30, 0, 0, utc), 1, jakarta, time.Date(2024, 1, 15, 6, 30, 0, 0, jakarta)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got, ok := rule.Next(tt.prev, tt.occurrence, tt.loc)
+			if tt.want.IsZero() {
+				if ok {
+					t.Fatalf("Next = %v, want end of series", got)
+				}
+				return
+			}
+			if !ok || !got.Equal(tt.want) {
+				t.Fatalf("Next = %v, %v; want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextDSTOffsets(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	rule, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := time.Date(2024, 3, 9, 9, 0, 0, 0, newYork)
+	next, ok := rule.Next(prev, 1, newYork)
+	if !ok {
+		t.Fatal("series ended")
+	}
+	// 09:00 EST is 14:00 UTC, 09:00 EDT is 13:00 UTC: only 23 hours apart.
+	if got := next.Sub(prev); got != 23*time.Hour {
+		t.Fatalf("gap = %v, want 23h", got)
+	}
+	if next.UTC().Hour() != 13 {
+		t.Fatalf("next = %v, want 13:00 UTC", next.UTC())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"RRULE:",
+		"FREQ=HOURLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;UNTIL=2024",
+		"FREQ=DAILY;UNTIL=20241301",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=DAILY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYDAY=MO;BYMONTHDAY=1",
+		"FREQ=DAILY;WKST=SU",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;INTERVAL",
+	} {
+		if _, err := Parse(rule); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", rule)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"RRULE:freq=weekly;byday=mo,fr;interval=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{"FREQ=MONTHLY;BYDAY=-1FR;COUNT=5", "FREQ=MONTHLY;BYDAY=-1FR;COUNT=5"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{"FREQ=DAILY;INTERVAL=1;WKST=MO", "FREQ=DAILY"},
+		{"FREQ=DAILY;UNTIL=20240103T090000Z", "FREQ=DAILY;UNTIL=20240103T090000Z"},
+		{"FREQ=DAILY;UNTIL=20240103T090000", "FREQ=DAILY;UNTIL=20240103T090000"},
+		{"FREQ=DAILY;UNTIL=20240103", "FREQ=DAILY;UNTIL=20240103T235959"},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+		again, err := Parse(rule.String())
+		if err != nil || again.String() != tt.want {
+			t.Errorf("String of %q does not round-trip: %v", tt.in, err)
+		}
+	}
+}
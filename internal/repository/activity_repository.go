@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type ActivityRepository interface {
+	Record(entries []models.TodoActivity) error
+	FindByTodo(todoID uint, limit int) ([]models.TodoActivity, error)
+}
+
+type activityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+func (r *activityRepository) Record(entries []models.TodoActivity) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.Create(&entries).Error
+}
+
+// FindByTodo returns the most recent changes, newest first. A limit of 0
+// returns all of them.
+func (r *activityRepository) FindByTodo(todoID uint, limit int) ([]models.TodoActivity, error) {
+	var out []models.TodoActivity
+	q := r.db.Where("todo_id = ?", todoID).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	FindByTodo(todoID uint) ([]models.Attachment, error)
+	FindByID(todoID, id uint) (*models.Attachment, error)
+	Create(a *models.Attachment) error
+	Delete(todoID, id uint) error
+	Usage(todoID uint) (count int64, bytes int64, err error)
+	StorageKeys(todoIDs ...uint) ([]string, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) FindByTodo(todoID uint) ([]models.Attachment, error) {
+	var out []models.Attachment
+	if err := r.db.Where("todo_id = ?", todoID).Order("created_at ASC, id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *attachmentRepository) FindByID(todoID, id uint) (*models.Attachment, error) {
+	var a models.Attachment
+	if err := r.db.Where("todo_id = ?", todoID).First(&a, id).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *attachmentRepository) Create(a *models.Attachment) error {
+	return r.db.Create(a).Error
+}
+
+func (r *attachmentRepository) Delete(todoID, id uint) error {
+	res := r.db.Where("todo_id = ?", todoID).Delete(&models.Attachment{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Usage reports how many attachments a todo has and their combined size.
+func (r *attachmentRepository) Usage(todoID uint) (int64, int64, error) {
+	var row struct {
+		Count int64
+		Bytes int64
+	}
+	err := r.db.Model(&models.Attachment{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(size), 0) AS bytes").
+		Where("todo_id = ?", todoID).
+		Scan(&row).Error
+	return row.Count, row.Bytes, err
+}
+
+// StorageKeys lists the blob keys of every attachment of the given todos,
+// so their files can be removed once the rows are gone.
+func (r *attachmentRepository) StorageKeys(todoIDs ...uint) ([]string, error) {
+	var keys []string
+	if len(todoIDs) == 0 {
+		return keys, nil
+	}
+	err := r.db.Model(&models.Attachment{}).Where("todo_id IN ?", todoIDs).Pluck("storage_key", &keys).Error
+	return keys, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	FindByTodo(todoID uint, limit int) ([]models.Comment, error)
+	FindByID(todoID, id uint) (*models.Comment, error)
+	Create(c *models.Comment) error
+	Update(c *models.Comment) error
+	Delete(todoID, id uint) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// FindByTodo returns the most recent comments, newest first. A limit of 0
+// returns all of them.
+func (r *commentRepository) FindByTodo(todoID uint, limit int) ([]models.Comment, error) {
+	var out []models.Comment
+	q := r.db.Preload("Author").Where("todo_id = ?", todoID).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *commentRepository) FindByID(todoID, id uint) (*models.Comment, error) {
+	var c models.Comment
+	if err := r.db.Preload("Author").Where("todo_id = ?", todoID).First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *commentRepository) Create(c *models.Comment) error {
+	return r.db.Omit("Author").Create(c).Error
+}
+
+func (r *commentRepository) Update(c *models.Comment) error {
+	return r.db.Omit("Author").Save(c).Error
+}
+
+func (r *commentRepository) Delete(todoID, id uint) error {
+	res := r.db.Where("todo_id = ?", todoID).Delete(&models.Comment{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
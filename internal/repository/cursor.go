@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a sorted listing: the sort it belongs to,
+// the sort key of the row and its id as a tie breaker.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   uint   `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// PageRequest selects one page of a listing. After and Before are
+// mutually exclusive; Offset is only used without a cursor.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	After  *Cursor
+	Before *Cursor
+	// Count also computes the total number of matching rows.
+	Count bool
+}
+
+type TodoPage struct {
+	Todos []models.Todo
+	Total *int64
+	Next  *Cursor
+	Prev  *Cursor
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository interface {
+	FindByOwner(ownerID uint, limit int) ([]models.ImportJob, error)
+	FindByID(id uint) (*models.ImportJob, error)
+	FindWithPayload(id uint) (*models.ImportJob, error)
+	Create(j *models.ImportJob) error
+	Update(j *models.ImportJob) error
+	Start(id uint, now, staleBefore time.Time) (bool, error)
+}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+// FindByOwner returns the owner's latest jobs, newest first, without
+// their payloads.
+func (r *importJobRepository) FindByOwner(ownerID uint, limit int) ([]models.ImportJob, error) {
+	var out []models.ImportJob
+	err := r.db.Omit("payload").
+		Where("owner_id = ?", ownerID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *importJobRepository) FindByID(id uint) (*models.ImportJob, error) {
+	var j models.ImportJob
+	if err := r.db.Omit("payload").First(&j, id).Error; err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// FindWithPayload returns a job including its uploaded file.
+func (r *importJobRepository) FindWithPayload(id uint) (*models.ImportJob, error) {
+	var j models.ImportJob
+	if err := r.db.First(&j, id).Error; err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *importJobRepository) Create(j *models.ImportJob) error {
+	return r.db.Create(j).Error
+}
+
+func (r *importJobRepository) Update(j *models.ImportJob) error {
+	return r.db.Save(j).Error
+}
+
+// Start marks a queued job as running and reports whether it did. A job
+// already running is only taken over when it started before staleBefore,
+// i.e. its earlier run is presumed dead; otherwise Start reports false.
+func (r *importJobRepository) Start(id uint, now, staleBefore time.Time) (bool, error) {
+	res := r.db.Model(&models.ImportJob{}).
+		Where("id = ? AND (status = ? OR (status = ? AND started_at < ?))",
+			id, models.ImportJobQueued, models.ImportJobRunning, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     models.ImportJobRunning,
+			"started_at": now,
+			"updated_at": now,
+		})
+	return res.RowsAffected > 0, res.Error
+}
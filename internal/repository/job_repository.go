@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrJobLeaseLost is returned by Touch and Finish when the job is no longer
+// held by the claim that is renewing or finishing it: it was reclaimed as
+// stale and possibly claimed again.
+var ErrJobLeaseLost = errors.New("job is no longer held by this claim")
+
+// JobRepository stores background jobs. Bound to a transaction, a job
+// enqueued by a write only becomes visible to workers when it commits.
+type JobRepository interface {
+	Enqueue(job *models.Job) (bool, error)
+	Claim(types []string, now time.Time) (*models.Job, error)
+	Touch(job *models.Job, now time.Time) error
+	Finish(job *models.Job) error
+	ReclaimStale(lockedBefore time.Time) (int, error)
+	FindAll(status models.JobStatus, typ string, beforeID uint, limit int) ([]models.Job, error)
+	FindByID(id uint) (*models.Job, error)
+	CountByStatus() (map[models.JobStatus]int64, error)
+	Retry(id uint, now time.Time) error
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Enqueue inserts job and reports whether it was created. When a job with
+// the same unique key already exists, job is replaced by that one instead.
+func (r *jobRepository) Enqueue(job *models.Job) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "unique_key"}}, DoNothing: true}).Create(job)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 || job.UniqueKey == nil {
+		return true, nil
+	}
+	return false, r.db.Where("unique_key = ?", *job.UniqueKey).First(job).Error
+}
+
+// claimSQL marks the oldest due job of one of the given types as running.
+// SKIP LOCKED lets concurrent workers claim different jobs without
+// waiting on each other.
+const claimSQL = `
+UPDATE jobs SET status = 'running', attempts = attempts + 1, locked_at = ?, updated_at = ?
+WHERE id = (
+	SELECT id FROM jobs
+	WHERE status = 'queued' AND run_at <= ? AND type IN ?
+	ORDER BY run_at, id
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id`
+
+// Claim returns the job it claimed, or nil when none is due.
+func (r *jobRepository) Claim(types []string, now time.Time) (*models.Job, error) {
+	var ids []uint
+	if err := r.db.Raw(claimSQL, now, now, now, types).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.FindByID(ids[0])
+}
+
+// claimed matches the job only while it is still held by the claim job was
+// read from: every claim bumps attempts, so a job that was reclaimed and
+// claimed again no longer matches.
+func (r *jobRepository) claimed(job *models.Job) *gorm.DB {
+	return r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, models.JobRunning, job.Attempts)
+}
+
+// Touch renews the lease of a running job so ReclaimStale leaves it alone.
+func (r *jobRepository) Touch(job *models.Job, now time.Time) error {
+	res := r.claimed(job).Updates(map[string]interface{}{"locked_at": now, "updated_at": now})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobLeaseLost
+	}
+	return nil
+}
+
+// Finish stores the outcome of a claimed job's attempt, unless the claim
+// was lost in the meantime.
+func (r *jobRepository) Finish(job *models.Job) error {
+	res := r.claimed(job).Updates(map[string]interface{}{
+		"status":      job.Status,
+		"run_at":      job.RunAt,
+		"last_error":  job.LastError,
+		"locked_at":   nil,
+		"finished_at": job.FinishedAt,
+		"updated_at":  time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobLeaseLost
+	}
+	return nil
+}
+
+// ReclaimStale requeues jobs whose lease was last renewed before
+// lockedBefore, i.e. left running by a worker that died, or fails them
+// when they have no attempts left.
+func (r *jobRepository) ReclaimStale(lockedBefore time.Time) (int, error) {
+	res := r.db.Exec(`
+UPDATE jobs SET
+	status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'queued' END,
+	finished_at = CASE WHEN attempts >= max_attempts THEN now() END,
+	last_error = 'worker stopped while running the job',
+	locked_at = NULL,
+	updated_at = now()
+WHERE status = 'running' AND locked_at < ?`, lockedBefore)
+	return int(res.RowsAffected), res.Error
+}
+
+// FindAll returns jobs newest first, optionally only those with status or
+// typ and IDs below beforeID.
+func (r *jobRepository) FindAll(status models.JobStatus, typ string, beforeID uint, limit int) ([]models.Job, error) {
+	q := r.db.Model(&models.Job{})
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if typ != "" {
+		q = q.Where("type = ?", typ)
+	}
+	if beforeID > 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+	var out []models.Job
+	if err := q.Order("id DESC").Limit(limit).Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *jobRepository) FindByID(id uint) (*models.Job, error) {
+	var j models.Job
+	if err := r.db.First(&j, id).Error; err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *jobRepository) CountByStatus() (map[models.JobStatus]int64, error) {
+	var rows []struct {
+		Status models.JobStatus
+		Count  int64
+	}
+	if err := r.db.Model(&models.Job{}).Select("status, count(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[models.JobStatus]int64, len(rows))
+	for _, row := range rows {
+		out[row.Status] = row.Count
+	}
+	return out, nil
+}
+
+// Retry queues a failed job to run again with a fresh set of attempts.
+func (r *jobRepository) Retry(id uint, now time.Time) error {
+	res := r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobFailed).
+		Updates(map[string]interface{}{
+			"status":      models.JobQueued,
+			"attempts":    0,
+			"run_at":      now,
+			"finished_at": nil,
+			"updated_at":  now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
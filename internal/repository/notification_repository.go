@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(n *models.Notification) error
+	FindAll(userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error)
+	MarkRead(userID, id uint) (*models.Notification, error)
+	MarkAllRead(userID uint) (int64, error)
+	CountUnread(userID uint) (int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(n *models.Notification) error {
+	return r.db.Create(n).Error
+}
+
+func (r *notificationRepository) FindAll(userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error) {
+	var out []models.Notification
+	q := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&out).Error; err != nil {
+		return nil, 0, err
+	}
+	return out, count, nil
+}
+
+func (r *notificationRepository) MarkRead(userID, id uint) (*models.Notification, error) {
+	var n models.Notification
+	if err := r.db.Where("user_id = ?", userID).First(&n, id).Error; err != nil {
+		return nil, err
+	}
+	if n.ReadAt != nil {
+		return &n, nil
+	}
+	now := time.Now()
+	if err := r.db.Model(&n).Update("read_at", now).Error; err != nil {
+		return nil, err
+	}
+	n.ReadAt = &now
+	return &n, nil
+}
+
+func (r *notificationRepository) MarkAllRead(userID uint) (int64, error) {
+	res := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return res.RowsAffected, res.Error
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository records events next to the writes that cause them;
+// bound to a transaction, the events commit or roll back with it.
+type OutboxRepository interface {
+	Add(events ...models.OutboxEvent) error
+	FindAfter(userID, afterID uint, typePrefix string, limit int) ([]models.OutboxEvent, bool, error)
+	LastID() (uint, error)
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.OutboxEvent, error)
+	Record(e *models.OutboxEvent) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(events ...models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Create(&events).Error
+}
+
+// settledBefore is the oldest transaction still running. Every event
+// recorded by an older transaction (tx_id below it) has committed or
+// rolled back, so no event can show up before it in (tx_id, id) order.
+// Event IDs alone cannot be streamed in order: they are assigned at insert,
+// and a transaction holding a lower ID may commit after one holding a
+// higher ID.
+//
+// The snapshot's xmin is cluster-wide, so any long transaction, not only
+// one recording events, holds back every stream. The hold-back is therefore
+// bounded by MaxHoldBack: an event older than that is streamed anyway. A
+// transaction that stays open longer than MaxHoldBack and then commits
+// events ordered before ones already streamed is not replayed to open
+// streams; clients see those changes on their next full fetch.
+const settledBefore = "pg_snapshot_xmin(pg_current_snapshot())::text::bigint"
+
+// MaxHoldBack is how long an event waits for older transactions to settle
+// before it is streamed regardless.
+const MaxHoldBack = 10 * time.Second
+
+// outboxRow is an event with its stream position.
+type outboxRow struct {
+	models.OutboxEvent
+	TxID    int64
+	Settled bool
+}
+
+// FindAfter returns a user's events that follow event afterID in stream
+// order, (tx_id, id), and whose type starts with typePrefix. It stops at
+// the first event recorded by a transaction that is not settled yet, and
+// then reports pending, so a caller streaming the events does not skip one
+// that commits later; it should ask again shortly. Events older than
+// MaxHoldBack count as settled.
+func (r *outboxRepository) FindAfter(userID, afterID uint, typePrefix string, limit int) ([]models.OutboxEvent, bool, error) {
+	q := r.db.Table("outbox_events").
+		Select("outbox_events.*, ("+settledBefore+" > tx_id OR created_at < ?) AS settled", time.Now().Add(-MaxHoldBack)).
+		Where("user_id = ? AND type LIKE ?", userID, typePrefix+"%")
+	if afterID > 0 {
+		var cursor []int64
+		if err := r.db.Table("outbox_events").Where("id = ?", afterID).Pluck("tx_id", &cursor).Error; err != nil {
+			return nil, false, err
+		}
+		if len(cursor) == 1 {
+			q = q.Where("(tx_id, id) > (?, ?)", cursor[0], afterID)
+		} else {
+			q = q.Where("id > ?", afterID)
+		}
+	}
+	var rows []outboxRow
+	if err := q.Order("tx_id ASC, id ASC").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, false, err
+	}
+	out := make([]models.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		if !row.Settled {
+			return out, true, nil
+		}
+		out = append(out, row.OutboxEvent)
+	}
+	return out, false, nil
+}
+
+// LastID returns the ID of the newest settled event in stream order, or 0
+// when there are none. Streaming from it skips nothing that commits later,
+// within the MaxHoldBack bound.
+func (r *outboxRepository) LastID() (uint, error) {
+	var ids []uint
+	err := r.db.Table("outbox_events").
+		Where("tx_id < "+settledBefore+" OR created_at < ?", time.Now().Add(-MaxHoldBack)).
+		Order("tx_id DESC, id DESC").
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// ClaimDue leases up to limit unprocessed events whose next attempt is
+// due, oldest first: in a short transaction it locks them with FOR UPDATE
+// SKIP LOCKED, so concurrent instances never claim the same event, and
+// pushes their next attempt lease into the future. The caller dispatches
+// them after the claim committed and stores each outcome with Record; an
+// event whose instance died before that is due again once the lease runs
+// out.
+func (r *outboxRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	var due []models.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("processed_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", now).
+			Order("id ASC").
+			Limit(limit).
+			Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+		ids := make([]uint, len(due))
+		for i := range due {
+			ids[i] = due[i].ID
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// Record stores the processing state of a claimed event.
+func (r *outboxRepository) Record(e *models.OutboxEvent) error {
+	return r.db.Model(&models.OutboxEvent{}).Where("id = ?", e.ID).Updates(map[string]interface{}{
+		"attempts":        e.Attempts,
+		"next_attempt_at": e.NextAttemptAt,
+		"last_error":      e.LastError,
+		"processed_at":    e.ProcessedAt,
+	}).Error
+}
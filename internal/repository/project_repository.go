@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type ProjectRepository interface {
+	FindAccessible(userID uint) ([]models.Project, error)
+	FindByID(id uint) (*models.Project, error)
+	FindByName(ownerID uint, name string) (*models.Project, error)
+	Create(p *models.Project) error
+	Update(p *models.Project) error
+	Delete(id uint) error
+}
+
+type projectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// FindAccessible returns projects owned by or shared with the user.
+func (r *projectRepository) FindAccessible(userID uint) ([]models.Project, error) {
+	var out []models.Project
+	err := r.db.
+		Where("owner_id = ? OR id IN (SELECT project_id FROM shares WHERE user_id = ? AND project_id IS NOT NULL)", userID, userID).
+		Order("name ASC, id ASC").
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *projectRepository) FindByID(id uint) (*models.Project, error) {
+	var p models.Project
+	if err := r.db.First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// FindByName returns the owner's oldest project with that name, ignoring
+// case.
+func (r *projectRepository) FindByName(ownerID uint, name string) (*models.Project, error) {
+	var p models.Project
+	err := r.db.Where("owner_id = ? AND lower(name) = lower(?)", ownerID, name).Order("id ASC").First(&p).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *projectRepository) Create(p *models.Project) error {
+	return r.db.Create(p).Error
+}
+
+func (r *projectRepository) Update(p *models.Project) error {
+	return r.db.Save(p).Error
+}
+
+func (r *projectRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Project{}, id).Error
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository interface {
+	FindByTodo(todoID uint) ([]models.Reminder, error)
+	FindByID(todoID, id uint) (*models.Reminder, error)
+	Create(r *models.Reminder) error
+	Delete(todoID, id uint) error
+	Reschedule(todoID uint, due *time.Time) error
+	ClaimDue(now time.Time, limit int) ([]models.Reminder, error)
+	SetError(id uint, msg string) error
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) FindByTodo(todoID uint) ([]models.Reminder, error) {
+	var out []models.Reminder
+	if err := r.db.Where("todo_id = ?", todoID).Order("fire_at ASC NULLS LAST, id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *reminderRepository) FindByID(todoID, id uint) (*models.Reminder, error) {
+	var rem models.Reminder
+	if err := r.db.Where("todo_id = ?", todoID).First(&rem, id).Error; err != nil {
+		return nil, err
+	}
+	return &rem, nil
+}
+
+func (r *reminderRepository) Create(rem *models.Reminder) error {
+	return r.db.Create(rem).Error
+}
+
+func (r *reminderRepository) Delete(todoID, id uint) error {
+	res := r.db.Where("todo_id = ?", todoID).Delete(&models.Reminder{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Reschedule recomputes fire_at of pending offset-based reminders after the
+// todo's due date changed. Without a due date they stay dormant.
+func (r *reminderRepository) Reschedule(todoID uint, due *time.Time) error {
+	q := r.db.Model(&models.Reminder{}).
+		Where("todo_id = ? AND offset_minutes IS NOT NULL AND fired_at IS NULL", todoID)
+	if due == nil {
+		return q.Update("fire_at", nil).Error
+	}
+	return q.Update("fire_at", gorm.Expr("?::timestamptz - offset_minutes * interval '1 minute'", *due)).Error
+}
+
+// ClaimDue marks up to limit due reminders fired and returns them. Rows
+// are locked with FOR UPDATE SKIP LOCKED so concurrent instances never
+// claim the same reminder; callers run it in the transaction that queues
+// the deliveries, so a reminder is only marked fired if they are queued.
+func (r *reminderRepository) ClaimDue(now time.Time, limit int) ([]models.Reminder, error) {
+	var due []models.Reminder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("fired_at IS NULL AND fire_at IS NOT NULL AND fire_at <= ?", now).
+		Order("fire_at ASC").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil || len(due) == 0 {
+		return nil, err
+	}
+	ids := make([]uint, len(due))
+	for i := range due {
+		ids[i] = due[i].ID
+		due[i].FiredAt = &now
+	}
+	if err := r.db.Model(&models.Reminder{}).Where("id IN ?", ids).Update("fired_at", now).Error; err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// SetError records why a claimed reminder could not be delivered.
+func (r *reminderRepository) SetError(id uint, msg string) error {
+	return r.db.Model(&models.Reminder{}).Where("id = ?", id).Update("last_error", truncate(msg, 500)).Error
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
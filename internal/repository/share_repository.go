@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShareRepository interface {
+	FindByTodo(todoID uint) ([]models.Share, error)
+	FindByProject(projectID uint) ([]models.Share, error)
+	Upsert(s *models.Share) error
+	DeleteTodoShare(todoID, userID uint) error
+	DeleteProjectShare(projectID, userID uint) error
+	// PermissionFor returns the strongest permission the user holds on the
+	// todo, either directly or through its project.
+	PermissionFor(userID, todoID uint, projectID *uint) (models.Permission, error)
+	ProjectPermissionFor(userID, projectID uint) (models.Permission, error)
+}
+
+type shareRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) ShareRepository {
+	return &shareRepository{db: db}
+}
+
+func (r *shareRepository) FindByTodo(todoID uint) ([]models.Share, error) {
+	var out []models.Share
+	if err := r.db.Preload("User").Where("todo_id = ?", todoID).Order("id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *shareRepository) FindByProject(projectID uint) ([]models.Share, error) {
+	var out []models.Share
+	if err := r.db.Preload("User").Where("project_id = ?", projectID).Order("id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *shareRepository) Upsert(s *models.Share) error {
+	target := "todo_id"
+	if s.ProjectID != nil {
+		target = "project_id"
+	}
+	return r.db.Omit("User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: target}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"permission"}),
+	}).Create(s).Error
+}
+
+func (r *shareRepository) DeleteTodoShare(todoID, userID uint) error {
+	res := r.db.Where("todo_id = ? AND user_id = ?", todoID, userID).Delete(&models.Share{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *shareRepository) DeleteProjectShare(projectID, userID uint) error {
+	res := r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&models.Share{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *shareRepository) PermissionFor(userID, todoID uint, projectID *uint) (models.Permission, error) {
+	q := r.db.Model(&models.Share{}).Where("user_id = ?", userID)
+	if projectID != nil {
+		q = q.Where("todo_id = ? OR project_id = ?", todoID, *projectID)
+	} else {
+		q = q.Where("todo_id = ?", todoID)
+	}
+	return strongest(q)
+}
+
+func (r *shareRepository) ProjectPermissionFor(userID, projectID uint) (models.Permission, error) {
+	return strongest(r.db.Model(&models.Share{}).Where("user_id = ? AND project_id = ?", userID, projectID))
+}
+
+func strongest(q *gorm.DB) (models.Permission, error) {
+	var perms []models.Permission
+	if err := q.Pluck("permission", &perms).Error; err != nil {
+		return "", err
+	}
+	var best models.Permission
+	for _, p := range perms {
+		if p == models.PermissionEditor {
+			return p, nil
+		}
+		best = p
+	}
+	return best, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	FindOrCreate(names []string) ([]models.Tag, error)
+	Attach(todoID uint, tags []models.Tag) error
+	Detach(todoID uint, tags []models.Tag) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindOrCreate returns the tags with the given (already normalized) names,
+// creating the missing ones.
+func (r *tagRepository) FindOrCreate(names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	rows := make([]models.Tag, len(names))
+	for i, n := range names {
+		rows[i] = models.Tag{Name: n}
+	}
+	if err := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	var tags []models.Tag
+	if err := r.db.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Attach(todoID uint, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return r.db.Omit("Tags.*").Model(&models.Todo{ID: todoID}).Association("Tags").Append(&tags)
+}
+
+func (r *tagRepository) Detach(todoID uint, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Todo{ID: todoID}).Association("Tags").Delete(&tags)
+}
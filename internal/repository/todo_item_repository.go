@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type TodoItemRepository interface {
+	FindByTodo(todoID uint) ([]models.TodoItem, error)
+	FindByID(todoID, id uint) (*models.TodoItem, error)
+	Create(item *models.TodoItem) error
+	Update(item *models.TodoItem) error
+	Delete(todoID, id uint) error
+	NextPosition(todoID uint) (int, error)
+	Reorder(todoID uint, ids []uint) error
+	SetAllCompleted(todoID uint, completed bool) error
+	Progress(todoIDs []uint) (map[uint]models.Progress, error)
+}
+
+type todoItemRepository struct {
+	db *gorm.DB
+}
+
+func NewTodoItemRepository(db *gorm.DB) TodoItemRepository {
+	return &todoItemRepository{db: db}
+}
+
+func (r *todoItemRepository) FindByTodo(todoID uint) ([]models.TodoItem, error) {
+	var items []models.TodoItem
+	if err := r.db.Where("todo_id = ?", todoID).Order("position ASC, id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *todoItemRepository) FindByID(todoID, id uint) (*models.TodoItem, error) {
+	var item models.TodoItem
+	if err := r.db.Where("todo_id = ?", todoID).First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *todoItemRepository) Create(item *models.TodoItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *todoItemRepository) Update(item *models.TodoItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *todoItemRepository) Delete(todoID, id uint) error {
+	res := r.db.Where("todo_id = ?", todoID).Delete(&models.TodoItem{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *todoItemRepository) NextPosition(todoID uint) (int, error) {
+	var max *int
+	if err := r.db.Model(&models.TodoItem{}).Where("todo_id = ?", todoID).Select("MAX(position)").Scan(&max).Error; err != nil {
+		return 0, err
+	}
+	if max == nil {
+		return 0, nil
+	}
+	return *max + 1, nil
+}
+
+// Reorder assigns positions following the order of ids. Items of the todo
+// that are not listed keep their relative order after the listed ones.
+func (r *todoItemRepository) Reorder(todoID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var items []models.TodoItem
+		if err := tx.Where("todo_id = ?", todoID).Order("position ASC, id ASC").Find(&items).Error; err != nil {
+			return err
+		}
+		rank := make(map[uint]int, len(ids))
+		for i, id := range ids {
+			rank[id] = i
+		}
+		pos := len(ids)
+		for _, it := range items {
+			p, ok := rank[it.ID]
+			if !ok {
+				p = pos
+				pos++
+			}
+			if err := tx.Model(&models.TodoItem{}).Where("id = ?", it.ID).Update("position", p).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *todoItemRepository) SetAllCompleted(todoID uint, completed bool) error {
+	return r.db.Model(&models.TodoItem{}).Where("todo_id = ? AND completed <> ?", todoID, completed).Update("completed", completed).Error
+}
+
+func (r *todoItemRepository) Progress(todoIDs []uint) (map[uint]models.Progress, error) {
+	out := make(map[uint]models.Progress, len(todoIDs))
+	if len(todoIDs) == 0 {
+		return out, nil
+	}
+	var rows []struct {
+		TodoID uint
+		Done   int64
+		Total  int64
+	}
+	err := r.db.Model(&models.TodoItem{}).
+		Select("todo_id, COUNT(*) FILTER (WHERE completed) AS done, COUNT(*) AS total").
+		Where("todo_id IN ?", todoIDs).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.TodoID] = models.Progress{Done: row.Done, Total: row.Total}
+	}
+	return out, nil
+}
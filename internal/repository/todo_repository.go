@@ -1,17 +1,72 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/filter"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
 )
 
+// TodoFilter narrows FindAll. With IncludeShared, OwnerID also matches
+// todos shared with that user directly or through a project. Where is a
+// parsed filter expression over TodoFilterFields.
+type TodoFilter struct {
+	Search        string
+	Completed     *bool
+	Priority      *models.Priority
+	ProjectID     *uint
+	Sort          string
+	OwnerID       *uint
+	IncludeShared bool
+	Where         filter.Node
+}
+
+// TodoFilterFields are the fields usable in todo filter expressions.
+var TodoFilterFields = filter.Fields{
+	"title":       {Column: "title", Kind: filter.Text},
+	"description": {Column: "description", Kind: filter.Text},
+	"completed":   {Column: "completed", Kind: filter.Bool},
+	"priority":    {Column: "priority", Kind: filter.Enum, Values: []string{"low", "medium", "high"}},
+	"due_date":    {Column: "due_date", Kind: filter.Time, Nullable: true},
+	"created_at":  {Column: "created_at", Kind: filter.Time},
+	"updated_at":  {Column: "updated_at", Kind: filter.Time},
+	"project_id":  {Column: "project_id", Kind: filter.Int, Nullable: true},
+	"owner_id":    {Column: "owner_id", Kind: filter.Int},
+	"tag": {
+		Column: "tags.name",
+		Kind:   filter.Text,
+		Wrap:   "EXISTS (SELECT 1 FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE todo_tags.todo_id = todos.id AND %s)",
+	},
+}
+
+// ErrVersionConflict is returned by Update when the todo was changed since
+// it was read.
+var ErrVersionConflict = errors.New("todo was modified by someone else")
+
 type TodoRepository interface {
-	FindAll(limit, offset int, search string, completed *bool, priority *models.Priority, sort string, ownerID *uint) ([]models.Todo, int64, error)
+	FindAll(filter TodoFilter, page PageRequest) (*TodoPage, error)
 	FindByID(id uint) (*models.Todo, error)
 	Create(todo *models.Todo) error
 	Update(todo *models.Todo) error
 	Delete(id uint) error
-	ToggleComplete(id uint, completed bool) (*models.Todo, error)
+	ToggleComplete(id uint, completed bool, ifMatch *uint) (*models.Todo, error)
+	Lock(id uint) error
+	FindOccurrence(seriesID uint, occurrence int) (*models.Todo, error)
+	FindByExternalID(ownerID uint, externalID string) (*models.Todo, error)
+	FindUpdatedSince(ownerID uint, since time.Time) ([]models.Todo, error)
+	SyncState(ownerID uint) (*SyncState, error)
+}
+
+// SyncState summarises an owner's todos for change detection: the latest
+// modification time and how many todos there are.
+type SyncState struct {
+	Latest time.Time
+	Count  int64
 }
 
 type todoRepository struct {
@@ -22,48 +77,191 @@ func NewTodoRepository(db *gorm.DB) TodoRepository {
 	return &todoRepository{db: db}
 }
 
-func (r *todoRepository) FindAll(limit, offset int, search string, completed *bool, priority *models.Priority, sort string, ownerID *uint) ([]models.Todo, int64, error) {
-	var todos []models.Todo
+// FindAll returns one page of todos. With a cursor it seeks past the
+// cursor's row (keyset pagination) instead of skipping Offset rows, so
+// pages stay stable while todos are being added.
+func (r *todoRepository) FindAll(filter TodoFilter, page PageRequest) (*TodoPage, error) {
 	q := r.db.Model(&models.Todo{})
 
-	if search != "" {
-		q = q.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+	if filter.Search != "" {
+		q = q.Where("search_vector @@ "+searchQuery, filter.Search)
 	}
-	if completed != nil {
-		q = q.Where("completed = ?", *completed)
+	if filter.Completed != nil {
+		q = q.Where("completed = ?", *filter.Completed)
 	}
-	if priority != nil {
-		q = q.Where("priority = ?", *priority)
+	if filter.Priority != nil {
+		q = q.Where("priority = ?", *filter.Priority)
 	}
-	if ownerID != nil {
-		q = q.Where("owner_id = ?", *ownerID)
+	if filter.ProjectID != nil {
+		q = q.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.Where != nil {
+		sql, args := filter.Where.SQL()
+		q = q.Where(sql, args...)
+	}
+	if filter.OwnerID != nil {
+		uid := *filter.OwnerID
+		if filter.IncludeShared {
+			q = q.Where(
+				"owner_id = ? OR id IN (SELECT todo_id FROM shares WHERE user_id = ? AND todo_id IS NOT NULL)"+
+					" OR project_id IN (SELECT project_id FROM shares WHERE user_id = ? AND project_id IS NOT NULL)"+
+					" OR project_id IN (SELECT id FROM projects WHERE owner_id = ?)",
+				uid, uid, uid, uid,
+			)
+		} else {
+			q = q.Where("owner_id = ?", uid)
+		}
+	}
+
+	result := &TodoPage{}
+	if page.Count {
+		var count int64
+		if err := q.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		result.Total = &count
 	}
 
-	var count int64
-	if err := q.Count(&count).Error; err != nil {
-		return nil, 0, err
+	spec := sortSpecFor(filter.Sort, filter.Search)
+	if filter.Search != "" {
+		q = q.Select("todos.*, "+searchRank+" AS rank, "+searchSnippet+" AS snippet", filter.Search, filter.Search)
 	}
+	cursor, backward := page.After, false
+	if page.Before != nil {
+		cursor, backward = page.Before, true
+	}
+	if cursor != nil {
+		if cursor.Sort != spec.name {
+			return nil, ErrInvalidCursor
+		}
+		// Rows after the cursor in the direction of travel.
+		cmp := ">"
+		if spec.desc != backward {
+			cmp = "<"
+		}
+		args := append(append([]interface{}{}, spec.args...), cursor.Key, cursor.ID)
+		q = q.Where(fmt.Sprintf("(%s, id) %s (?::%s, ?)", spec.expr, cmp, spec.keyType()), args...)
+	} else if page.Offset > 0 {
+		q = q.Offset(page.Offset)
+	}
+	dir := "ASC"
+	if spec.desc != backward {
+		dir = "DESC"
+	}
+	q = q.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                fmt.Sprintf("%s %s, id %s", spec.expr, dir, dir),
+		Vars:               spec.args,
+		WithoutParentheses: true,
+	}})
+
+	var todos []models.Todo
+	if err := q.Preload("Tags").Limit(page.Limit + 1).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	more := len(todos) > page.Limit
+	if more {
+		todos = todos[:page.Limit]
+	}
+	if backward {
+		for i, j := 0, len(todos)-1; i < j; i, j = i+1, j-1 {
+			todos[i], todos[j] = todos[j], todos[i]
+		}
+	}
+	result.Todos = todos
+	if len(todos) == 0 {
+		return result, nil
+	}
+	first, last := spec.cursor(&todos[0]), spec.cursor(&todos[len(todos)-1])
+	if backward {
+		result.Next = &last
+		if more {
+			result.Prev = &first
+		}
+	} else {
+		if more {
+			result.Next = &last
+		}
+		if cursor != nil || page.Offset > 0 {
+			result.Prev = &first
+		}
+	}
+	return result, nil
+}
+
+// Full-text search fragments. Each takes the raw search string as its
+// only argument; websearch_to_tsquery accepts "quoted phrases", OR and
+// -negation and never fails on malformed input. The snippet escapes HTML
+// before highlighting so only the <mark> tags are markup.
+const (
+	searchQuery   = "websearch_to_tsquery(todo_search_config(), ?)"
+	searchRank    = "ts_rank_cd(search_vector, " + searchQuery + ")"
+	searchSnippet = "ts_headline(todo_search_config(), " +
+		"replace(replace(replace(coalesce(title, '') || ' ' || coalesce(description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), " +
+		searchQuery + ", 'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2')"
+)
+
+// sortSpec describes one supported ordering. expr never yields NULL, so
+// it can be compared as a row value; the infinity fallbacks reproduce
+// NULLS LAST for due dates in both directions. args are bound to the
+// placeholders in expr, and cast is the SQL type of the cursor key
+// (timestamptz when empty).
+type sortSpec struct {
+	name string
+	expr string
+	args []interface{}
+	cast string
+	desc bool
+	key  func(t *models.Todo) string
+}
+
+func (s sortSpec) keyType() string {
+	if s.cast == "" {
+		return "timestamptz"
+	}
+	return s.cast
+}
+
+func (s sortSpec) cursor(t *models.Todo) Cursor {
+	return Cursor{Sort: s.name, Key: s.key(t), ID: t.ID}
+}
 
+// sortSpecFor resolves a sort name. relevance needs a search term and
+// falls back to newest first without one.
+func sortSpecFor(sort, search string) sortSpec {
+	created := func(t *models.Todo) string { return t.CreatedAt.UTC().Format(time.RFC3339Nano) }
+	due := func(missing string) func(t *models.Todo) string {
+		return func(t *models.Todo) string {
+			if t.DueDate == nil {
+				return missing
+			}
+			return t.DueDate.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	if sort == "relevance" && search == "" {
+		sort = "created_desc"
+	}
 	switch sort {
+	case "relevance":
+		rank := func(t *models.Todo) string {
+			if t.Rank == nil {
+				return "0"
+			}
+			return strconv.FormatFloat(float64(*t.Rank), 'g', -1, 32)
+		}
+		return sortSpec{name: sort, expr: searchRank, args: []interface{}{search}, cast: "real", desc: true, key: rank}
 	case "due_asc":
-		q = q.Order("due_date ASC NULLS LAST")
+		return sortSpec{name: sort, expr: "COALESCE(due_date, 'infinity'::timestamptz)", key: due("infinity")}
 	case "due_desc":
-		q = q.Order("due_date DESC NULLS LAST")
+		return sortSpec{name: sort, expr: "COALESCE(due_date, '-infinity'::timestamptz)", desc: true, key: due("-infinity")}
 	case "created_desc":
-		q = q.Order("created_at DESC")
-	default:
-		q = q.Order("created_at ASC")
-	}
-
-	if err := q.Limit(limit).Offset(offset).Find(&todos).Error; err != nil {
-		return nil, 0, err
+		return sortSpec{name: sort, expr: "created_at", desc: true, key: created}
 	}
-	return todos, count, nil
+	return sortSpec{name: "created_asc", expr: "created_at", key: created}
 }
 
 func (r *todoRepository) FindByID(id uint) (*models.Todo, error) {
 	var todo models.Todo
-	if err := r.db.First(&todo, id).Error; err != nil {
+	if err := r.db.Preload("Tags").First(&todo, id).Error; err != nil {
 		return nil, err
 	}
 	return &todo, nil
@@ -73,22 +271,115 @@ func (r *todoRepository) Create(todo *models.Todo) error {
 	return r.db.Create(todo).Error
 }
 
+// Update writes todo only if its version is still the one that was read,
+// and bumps the version on success.
 func (r *todoRepository) Update(todo *models.Todo) error {
-	return r.db.Save(todo).Error
+	expected := todo.Version
+	todo.Version = expected + 1
+	res := r.db.Model(todo).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(todo)
+	if res.Error != nil {
+		todo.Version = expected
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		todo.Version = expected
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 func (r *todoRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Todo{}, id).Error
 }
 
-func (r *todoRepository) ToggleComplete(id uint, completed bool) (*models.Todo, error) {
-	todo, err := r.FindByID(id)
+// ToggleComplete sets completed and bumps the version. When ifMatch is set
+// the todo must still be at that version, or ErrVersionConflict is
+// returned.
+func (r *todoRepository) ToggleComplete(id uint, completed bool, ifMatch *uint) (*models.Todo, error) {
+	q := r.db.Model(&models.Todo{}).Where("id = ?", id)
+	if ifMatch != nil {
+		q = q.Where("version = ?", *ifMatch)
+	}
+	res := q.Updates(map[string]interface{}{
+		"completed": completed,
+		"version":   gorm.Expr("version + 1"),
+	})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		if ifMatch != nil {
+			return nil, ErrVersionConflict
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.FindByID(id)
+}
+
+// Lock takes a row lock on the todo (SELECT ... FOR UPDATE) that is held
+// until the surrounding transaction ends. Writes that check a limit on the
+// todo's data take it first so concurrent requests cannot both pass.
+func (r *todoRepository) Lock(id uint) error {
+	var t models.Todo
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&t, id).Error
+}
+
+func (r *todoRepository) FindOccurrence(seriesID uint, occurrence int) (*models.Todo, error) {
+	var todo models.Todo
+	if err := r.db.Where("series_id = ? AND occurrence = ?", seriesID, occurrence).First(&todo).Error; err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// FindByExternalID finds the owner's todo imported under externalID, or
+// the one exported as todo-<id> (see todoio.UID) if it has none.
+func (r *todoRepository) FindByExternalID(ownerID uint, externalID string) (*models.Todo, error) {
+	var todo models.Todo
+	err := r.db.Preload("Tags").
+		Where("owner_id = ?", ownerID).
+		Where("external_id = ? OR (external_id IS NULL AND 'todo-' || id = ?)", externalID, externalID).
+		Order("external_id NULLS LAST").
+		First(&todo).Error
 	if err != nil {
 		return nil, err
 	}
-	todo.Completed = completed
-	if err := r.db.Save(todo).Error; err != nil {
+	return &todo, nil
+}
+
+// FindUpdatedSince returns the owner's todos modified after since, all of
+// them for the zero time.
+func (r *todoRepository) FindUpdatedSince(ownerID uint, since time.Time) ([]models.Todo, error) {
+	var todos []models.Todo
+	q := r.db.Preload("Tags").Where("owner_id = ?", ownerID)
+	if !since.IsZero() {
+		q = q.Where("updated_at > ?", since)
+	}
+	if err := q.Order("id ASC").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) SyncState(ownerID uint) (*SyncState, error) {
+	var row struct {
+		Latest *time.Time
+		Count  int64
+	}
+	err := r.db.Model(&models.Todo{}).
+		Select("MAX(updated_at) AS latest, COUNT(*) AS count").
+		Where("owner_id = ?", ownerID).
+		Scan(&row).Error
+	if err != nil {
 		return nil, err
 	}
-	return todo, nil
+	state := &SyncState{Count: row.Count}
+	if row.Latest != nil {
+		state.Latest = *row.Latest
+	}
+	return state, nil
 }
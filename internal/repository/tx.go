@@ -0,0 +1,61 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repos bundles the repositories that take part in multi-step writes. The
+// repositories handed to a TxManager callback all share one transaction.
+type Repos struct {
+	Todos       TodoRepository
+	Items       TodoItemRepository
+	Reminders   ReminderRepository
+	Activity    ActivityRepository
+	Attachments AttachmentRepository
+	Tags        TagRepository
+	Projects    ProjectRepository
+	Shares      ShareRepository
+	Users       UserRepository
+	ImportJobs  ImportJobRepository
+	Outbox      OutboxRepository
+	Jobs        JobRepository
+
+	// Tx starts a nested transaction (a savepoint) inside the current one.
+	Tx TxManager
+}
+
+type TxManager interface {
+	// Do runs fn in a transaction that commits when fn returns nil and
+	// rolls back otherwise.
+	Do(fn func(r Repos) error) error
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) Do(fn func(r Repos) error) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		return fn(reposFor(tx))
+	})
+}
+
+func reposFor(db *gorm.DB) Repos {
+	return Repos{
+		Todos:       NewTodoRepository(db),
+		Items:       NewTodoItemRepository(db),
+		Reminders:   NewReminderRepository(db),
+		Activity:    NewActivityRepository(db),
+		Attachments: NewAttachmentRepository(db),
+		Tags:        NewTagRepository(db),
+		Projects:    NewProjectRepository(db),
+		Shares:      NewShareRepository(db),
+		Users:       NewUserRepository(db),
+		ImportJobs:  NewImportJobRepository(db),
+		Outbox:      NewOutboxRepository(db),
+		Jobs:        NewJobRepository(db),
+		Tx:          &txManager{db: db},
+	}
+}
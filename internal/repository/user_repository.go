@@ -11,7 +11,9 @@ type UserRepository interface {
 	Create(u *models.User) error
 	Update(u *models.User) error
 	SetPassword(id uint, hash string) error
-	SetAvatar(id uint, url string) error
+	SetAvatar(id uint, url string, sizes map[string]string) error
+	FindByFeedToken(hash string) (*models.User, error)
+	SetFeedToken(id uint, hash *string) error
 }
 
 type userRepository struct {
@@ -50,6 +52,23 @@ func (r *userRepository) SetPassword(id uint, hash string) error {
 	return r.db.Model(&models.User{}).Where("id = ?", id).Update("password_hash", hash).Error
 }
 
-func (r *userRepository) SetAvatar(id uint, url string) error {
-	return r.db.Model(&models.User{}).Where("id = ?", id).Update("avatar_url", url).Error
+func (r *userRepository) SetAvatar(id uint, url string, sizes map[string]string) error {
+	return r.db.Model(&models.User{ID: id}).
+		Select("avatar_url", "avatar_sizes").
+		Updates(&models.User{AvatarURL: url, AvatarSizes: sizes}).Error
+}
+
+// FindByFeedToken finds the user whose calendar feed token hashes to hash.
+func (r *userRepository) FindByFeedToken(hash string) (*models.User, error) {
+	var u models.User
+	if err := r.db.Where("feed_token_hash = ?", hash).First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// SetFeedToken stores a new feed token hash, or revokes the feed when
+// hash is nil.
+func (r *userRepository) SetFeedToken(id uint, hash *string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("feed_token_hash", hash).Error
 }
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+)
+
+type ViewRepository interface {
+	FindByOwner(ownerID uint) ([]models.SavedView, error)
+	FindByID(id uint) (*models.SavedView, error)
+	Create(v *models.SavedView) error
+	Update(v *models.SavedView) error
+	Delete(id uint) error
+}
+
+type viewRepository struct {
+	db *gorm.DB
+}
+
+func NewViewRepository(db *gorm.DB) ViewRepository {
+	return &viewRepository{db: db}
+}
+
+func (r *viewRepository) FindByOwner(ownerID uint) ([]models.SavedView, error) {
+	var out []models.SavedView
+	if err := r.db.Where("owner_id = ?", ownerID).Order("name ASC, id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *viewRepository) FindByID(id uint) (*models.SavedView, error) {
+	var v models.SavedView
+	if err := r.db.First(&v, id).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *viewRepository) Create(v *models.SavedView) error {
+	return r.db.Create(v).Error
+}
+
+func (r *viewRepository) Update(v *models.SavedView) error {
+	return r.db.Save(v).Error
+}
+
+func (r *viewRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SavedView{}, id).Error
+}
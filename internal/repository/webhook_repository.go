@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	FindByOwner(ownerID uint) ([]models.Webhook, error)
+	FindByID(id uint) (*models.Webhook, error)
+	Create(w *models.Webhook) error
+	Update(w *models.Webhook) error
+	Delete(id uint) error
+	FindDeliveries(webhookID uint, status models.DeliveryStatus, beforeID uint, limit int) ([]models.WebhookDelivery, error)
+	FindDelivery(webhookID, id uint) (*models.WebhookDelivery, error)
+	Redeliver(webhookID, id uint, now time.Time) error
+	Enqueue(eventID uint) (int, error)
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordAttempt(d *models.WebhookDelivery, attempt *models.WebhookAttempt) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) FindByOwner(ownerID uint) ([]models.Webhook, error) {
+	var out []models.Webhook
+	if err := r.db.Where("owner_id = ?", ownerID).Order("id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *webhookRepository) FindByID(id uint) (*models.Webhook, error) {
+	var w models.Webhook
+	if err := r.db.First(&w, id).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *webhookRepository) Create(w *models.Webhook) error {
+	return r.db.Create(w).Error
+}
+
+func (r *webhookRepository) Update(w *models.Webhook) error {
+	return r.db.Save(w).Error
+}
+
+func (r *webhookRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Webhook{}, id).Error
+}
+
+// FindDeliveries returns a webhook's deliveries newest first, optionally
+// only those with status and IDs below beforeID.
+func (r *webhookRepository) FindDeliveries(webhookID uint, status models.DeliveryStatus, beforeID uint, limit int) ([]models.WebhookDelivery, error) {
+	q := r.db.Where("webhook_id = ?", webhookID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if beforeID > 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+	var out []models.WebhookDelivery
+	if err := q.Order("id DESC").Limit(limit).Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FindDelivery returns a delivery with its attempt log.
+func (r *webhookRepository) FindDelivery(webhookID, id uint) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).
+		Preload("Logs", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&d, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Redeliver queues a delivery again with a fresh set of attempts, whatever
+// its state.
+func (r *webhookRepository) Redeliver(webhookID, id uint, now time.Time) error {
+	res := r.db.Model(&models.WebhookDelivery{}).
+		Where("webhook_id = ? AND id = ?", webhookID, id).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// enqueueSQL creates a pending delivery of an event for every active
+// webhook subscribed to it: the owner's webhooks and global ones. An
+// event relayed again gets no second delivery.
+const enqueueSQL = `
+INSERT INTO webhook_deliveries
+	(webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at)
+SELECT w.id, e.id, e.type,
+	jsonb_build_object('id', e.id, 'type', e.type, 'created_at', e.created_at, 'data', e.payload),
+	'pending', 0, now(), now(), now()
+FROM outbox_events e
+JOIN webhooks w ON w.active
+	AND w.events @> jsonb_build_array(e.type)
+	AND (w.global OR w.owner_id = e.user_id)
+WHERE e.id = ?
+ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+func (r *webhookRepository) Enqueue(eventID uint) (int, error) {
+	res := r.db.Exec(enqueueSQL, eventID)
+	return int(res.RowsAffected), res.Error
+}
+
+// ClaimDue leases up to limit pending deliveries whose next attempt is
+// due: in a short transaction it locks them with FOR UPDATE SKIP LOCKED,
+// so concurrent instances never claim the same delivery, and pushes their
+// next attempt lease into the future. The caller sends them after the
+// claim committed and reports each outcome with RecordAttempt; a delivery
+// whose instance died before that is due again once the lease runs out.
+// The returned deliveries have their Webhook loaded.
+func (r *webhookRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var due []models.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(due))
+		hookIDs := make([]uint, 0, len(due))
+		for _, d := range due {
+			ids = append(ids, d.ID)
+			hookIDs = append(hookIDs, d.WebhookID)
+		}
+		err = tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+		var hooks []models.Webhook
+		if err := tx.Where("id IN ?", hookIDs).Find(&hooks).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.Webhook, len(hooks))
+		for i := range hooks {
+			byID[hooks[i].ID] = &hooks[i]
+		}
+		for i := range due {
+			due[i].Webhook = byID[due[i].WebhookID]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// RecordAttempt logs an attempt at d and stores d's new state in one
+// transaction.
+func (r *webhookRepository) RecordAttempt(d *models.WebhookDelivery, attempt *models.WebhookAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt.DeliveryID = d.ID
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+			"status":           d.Status,
+			"attempts":         d.Attempts,
+			"next_attempt_at":  d.NextAttemptAt,
+			"last_status_code": d.LastStatusCode,
+			"last_error":       d.LastError,
+			"delivered_at":     d.DeliveredAt,
+			"updated_at":       time.Now(),
+		}).Error
+	})
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/handlers"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/middleware"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/realtime"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/service"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
 )
 
 //go:embed openapi/openapi.json
@@ -22,19 +24,19 @@ var openapiFS embed.FS
 //go:embed openapi/redoc.html
 var redocFS embed.FS
 
-func NewFiberApp(cfg *config.Config, db *gorm.DB) *fiber.App {
+func NewFiberApp(cfg *config.Config, db *gorm.DB, blobs storage.BlobStore, hub *realtime.Hub) *fiber.App {
 	app := fiber.New(fiber.Config{
 		AppName:      "Go Fiber GORM TODO + JWT + OpenAPI",
 		ReadTimeout:  cfg.ReadTimeout,
 		WriteTimeout: cfg.WriteTimeout,
+		BodyLimit:    bodyLimit(cfg),
+		// CalDAV clients also speak PROPFIND and REPORT
+		RequestMethods: append(append([]string{}, fiber.DefaultMethods...), handlers.DAVMethods...),
 	})
 
 	app.Use(recover.New())
 	app.Use(logger.New())
 
-	// Serve uploaded files
-	app.Static("/uploads", cfg.UploadDir)
-
 	app.Get("/healthz", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"status": "ok",
@@ -60,18 +62,83 @@ func NewFiberApp(cfg *config.Config, db *gorm.DB) *fiber.App {
 		return c.Send(b)
 	})
 
+	// Serve uploaded files from the blob store, optionally only through
+	// signed URLs handed out by the services.
+	urls := storage.NewURLSigner(cfg.UploadURLSecret, cfg.UploadURLTTL, cfg.UploadsSigned)
+	uploadHandler := handlers.NewUploadHandler(blobs, urls)
+	app.Get("/uploads/*", uploadHandler.Serve)
+
 	// DI
 	userRepo := repository.NewUserRepository(db)
-	authSvc := service.NewAuthService(cfg, userRepo)
+	outboxRepo := repository.NewOutboxRepository(db)
+	txm := repository.NewTxManager(db)
+	authSvc := service.NewAuthService(cfg, userRepo, txm, urls)
 	authHandler := handlers.NewAuthHandler(authSvc)
 
-	userSvc := service.NewUserService(userRepo)
-	profileHandler := handlers.NewProfileHandler(cfg, userSvc)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationSvc := service.NewNotificationService(notificationRepo)
+	notificationHandler := handlers.NewNotificationHandler(notificationSvc)
+
+	userSvc := service.NewUserService(userRepo, txm, blobs, urls)
+	profileHandler := handlers.NewProfileHandler(cfg, userSvc, notificationSvc)
+
+	projectRepo := repository.NewProjectRepository(db)
+	shareRepo := repository.NewShareRepository(db)
+	access := service.NewAccessControl(shareRepo, projectRepo)
 
 	todoRepo := repository.NewTodoRepository(db)
-	todoSvc := service.NewTodoService(todoRepo)
+	todoItemRepo := repository.NewTodoItemRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	activityRepo := repository.NewActivityRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	todoSvc := service.NewTodoService(todoRepo, todoItemRepo, reminderRepo, activityRepo, userRepo, attachmentRepo, outboxRepo, blobs, access, txm)
 	todoHandler := handlers.NewTodoHandler(todoSvc)
 
+	todoItemSvc := service.NewTodoItemService(todoRepo, todoSvc, todoItemRepo, access)
+	todoItemHandler := handlers.NewTodoItemHandler(todoItemSvc)
+
+	reminderSvc := service.NewReminderService(todoRepo, reminderRepo, access, cfg.SMTPHost != "")
+	reminderHandler := handlers.NewReminderHandler(reminderSvc)
+
+	projectSvc := service.NewProjectService(projectRepo, access)
+	projectHandler := handlers.NewProjectHandler(projectSvc)
+
+	shareSvc := service.NewShareService(shareRepo, todoRepo, projectRepo, userRepo, notificationSvc, urls)
+	shareHandler := handlers.NewShareHandler(shareSvc)
+
+	commentRepo := repository.NewCommentRepository(db)
+	commentSvc := service.NewCommentService(commentRepo, activityRepo, todoRepo, userRepo, access, notificationSvc, urls)
+	commentHandler := handlers.NewCommentHandler(commentSvc)
+
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, todoRepo, blobs, access, service.AttachmentLimitsFrom(cfg), urls, txm)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentSvc)
+
+	feedSvc := service.NewFeedService(userRepo, todoRepo)
+	feedHandler := handlers.NewFeedHandler(feedSvc)
+
+	caldavSvc := service.NewCalDAVService(todoRepo, userRepo, todoSvc)
+	caldavHandler := handlers.NewCalDAVHandler(caldavSvc, userSvc)
+
+	viewRepo := repository.NewViewRepository(db)
+	viewSvc := service.NewViewService(viewRepo, userRepo, todoSvc)
+	viewHandler := handlers.NewViewHandler(viewSvc)
+
+	importJobSvc := service.NewImportJobService(repository.NewImportJobRepository(db), userRepo, todoSvc, txm, cfg.JobStaleAfter)
+	importJobHandler := handlers.NewImportJobHandler(importJobSvc)
+
+	jobHandler := handlers.NewJobHandler(service.NewJobService(repository.NewJobRepository(db)))
+
+	webhookHandler := handlers.NewWebhookHandler(service.NewWebhookService(repository.NewWebhookRepository(db)))
+
+	eventHandler := handlers.NewEventHandler(service.NewEventService(outboxRepo), hub)
+
+	// Calendar subscription feeds, authenticated by the token in the URL
+	app.Get("/feeds/:token/todos.ics", feedHandler.Serve)
+
+	// CalDAV task sync, authenticated with HTTP Basic (email + password)
+	app.All("/.well-known/caldav", caldavHandler.WellKnown)
+	app.All(handlers.DAVPrefix+"/*", handlers.DAVAuth(authSvc), caldavHandler.Serve)
+
 	api := app.Group("/api/v1")
 
 	// Auth routes (public)
@@ -79,6 +146,10 @@ func NewFiberApp(cfg *config.Config, db *gorm.DB) *fiber.App {
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
 
+	// Live todo changes (SSE); registered before the protected group so the
+	// token may also come from the query string
+	api.Get("/events", middleware.JWTQuery(cfg), eventHandler.Stream)
+
 	// Protected routes
 	protected := api.Group("/", middleware.JWT(cfg))
 
@@ -87,18 +158,117 @@ func NewFiberApp(cfg *config.Config, db *gorm.DB) *fiber.App {
 	protected.Put("/me", profileHandler.Update)
 	protected.Patch("/me/password", profileHandler.ChangePassword)
 	protected.Post("/me/avatar", profileHandler.UploadAvatar)
+	protected.Post("/me/feed", feedHandler.Rotate)
+	protected.Delete("/me/feed", feedHandler.Revoke)
+
+	// Notification inbox
+	protected.Get("/notifications", notificationHandler.List)
+	protected.Post("/notifications/read-all", notificationHandler.MarkAllRead)
+	protected.Patch("/notifications/:id/read", notificationHandler.MarkRead)
+
+	// Projects
+	projects := protected.Group("/projects")
+	projects.Get("/", projectHandler.List)
+	projects.Post("/", projectHandler.Create)
+	projects.Get("/:id", projectHandler.Get)
+	projects.Put("/:id", projectHandler.Update)
+	projects.Delete("/:id", projectHandler.Delete)
+	projects.Get("/:id/shares", shareHandler.ListProject)
+	projects.Post("/:id/shares", shareHandler.ShareProject)
+	projects.Delete("/:id/shares/:userId", shareHandler.RevokeProject)
+
+	// Saved views; :id is a view ID or a system view key
+	views := protected.Group("/views")
+	views.Get("/", viewHandler.List)
+	views.Post("/", viewHandler.Create)
+	views.Get("/:id", viewHandler.Get)
+	views.Put("/:id", viewHandler.Update)
+	views.Delete("/:id", viewHandler.Delete)
+	views.Get("/:id/todos", viewHandler.Todos)
+
+	// Imports from Todoist, Trello and Microsoft To Do, run as jobs
+	imports := protected.Group("/imports")
+	imports.Get("/", importJobHandler.List)
+	imports.Post("/", importJobHandler.Create)
+	imports.Get("/:id", importJobHandler.Get)
+
+	// Outgoing webhooks and their delivery logs
+	webhooks := protected.Group("/webhooks")
+	webhooks.Get("/", webhookHandler.List)
+	webhooks.Post("/", webhookHandler.Create)
+	webhooks.Get("/:id", webhookHandler.Get)
+	webhooks.Put("/:id", webhookHandler.Update)
+	webhooks.Delete("/:id", webhookHandler.Delete)
+	webhooks.Post("/:id/secret", webhookHandler.RotateSecret)
+	webhooks.Get("/:id/deliveries", webhookHandler.Deliveries)
+	webhooks.Get("/:id/deliveries/:deliveryId", webhookHandler.Delivery)
+	webhooks.Post("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+
+	// Background job queue (admin only)
+	jobs := protected.Group("/admin/jobs", middleware.RequireRoles("admin"))
+	jobs.Get("/", jobHandler.List)
+	jobs.Get("/:id", jobHandler.Get)
+	jobs.Post("/:id/retry", jobHandler.Retry)
 
 	// Todos for authenticated users
 	todos := protected.Group("/todos")
 	todos.Get("/", todoHandler.List)
+	todos.Get("/export", todoHandler.Export)
+	todos.Post("/import", todoHandler.Import)
 	todos.Get("/:id", todoHandler.Get)
 	todos.Post("/", todoHandler.Create)
+	todos.Post("/bulk", todoHandler.Bulk)
 	todos.Put("/:id", todoHandler.Update)
+	todos.Patch("/:id", todoHandler.Patch)
 	todos.Patch("/:id/toggle", todoHandler.Toggle)
 
+	// Sub-resources are registered before the admin guard below so that
+	// their DELETE routes stay open to regular users.
+
+	// Checklist items
+	todos.Get("/:id/items", todoItemHandler.List)
+	todos.Post("/:id/items", todoItemHandler.Create)
+	todos.Put("/:id/items/order", todoItemHandler.Reorder)
+	todos.Put("/:id/items/:itemId", todoItemHandler.Update)
+	todos.Patch("/:id/items/:itemId/toggle", todoItemHandler.Toggle)
+	todos.Delete("/:id/items/:itemId", todoItemHandler.Delete)
+
+	// Reminders
+	todos.Get("/:id/reminders", reminderHandler.List)
+	todos.Post("/:id/reminders", reminderHandler.Create)
+	todos.Delete("/:id/reminders/:reminderId", reminderHandler.Delete)
+
+	// Collaborators
+	todos.Get("/:id/shares", shareHandler.ListTodo)
+	todos.Post("/:id/shares", shareHandler.ShareTodo)
+	todos.Delete("/:id/shares/:userId", shareHandler.RevokeTodo)
+
+	// Comments and activity
+	todos.Get("/:id/comments", commentHandler.List)
+	todos.Post("/:id/comments", commentHandler.Create)
+	todos.Put("/:id/comments/:commentId", commentHandler.Update)
+	todos.Delete("/:id/comments/:commentId", commentHandler.Delete)
+	todos.Get("/:id/activity", commentHandler.Activity)
+
+	// Attachments
+	todos.Get("/:id/attachments", attachmentHandler.List)
+	todos.Post("/:id/attachments", attachmentHandler.Upload)
+	todos.Get("/:id/attachments/:attachmentId/download", attachmentHandler.Download)
+	todos.Delete("/:id/attachments/:attachmentId", attachmentHandler.Delete)
+
 	// Admin-only delete
 	admin := todos.Use(middleware.RequireRoles("admin"))
 	admin.Delete("/:id", todoHandler.Delete)
 
 	return app
 }
+
+// bodyLimit leaves room for the multipart framing around the largest
+// attachment while never going below Fiber's default.
+func bodyLimit(cfg *config.Config) int {
+	limit := int(cfg.AttachmentMaxBytes) + 1<<20
+	if limit < fiber.DefaultBodyLimit {
+		return fiber.DefaultBodyLimit
+	}
+	return limit
+}
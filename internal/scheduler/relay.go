@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/events"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/jobs"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/notify"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// Failed events are retried after relayBaseDelay, doubling per attempt up
+// to relayMaxDelay. A claimed event is due again after relayLease if the
+// instance relaying it died.
+const (
+	relayBaseDelay = 10 * time.Second
+	relayMaxDelay  = time.Hour
+	relayLease     = 5 * time.Minute
+)
+
+// EventRelay dispatches the events recorded in the outbox to the bus. An
+// event is marked processed once every subscriber handled it; when one
+// fails the whole event is retried with backoff, and given up on after
+// maxAttempts with its last error kept. Events are claimed in a short
+// transaction and dispatched after it committed, so subscribers never run
+// while outbox rows are locked. Like the reminder scheduler it is safe to
+// run in every app instance.
+type EventRelay struct {
+	outbox      repository.OutboxRepository
+	bus         *events.Bus
+	interval    time.Duration
+	batch       int
+	maxAttempts int
+}
+
+func NewEventRelay(
+	outbox repository.OutboxRepository,
+	bus *events.Bus,
+	interval time.Duration,
+	batch int,
+	maxAttempts int,
+) *EventRelay {
+	return &EventRelay{
+		outbox:      outbox,
+		bus:         bus,
+		interval:    interval,
+		batch:       batch,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (r *EventRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *EventRelay) tick(ctx context.Context) {
+	for {
+		due, err := r.outbox.ClaimDue(time.Now(), r.batch, relayLease)
+		if err != nil {
+			log.Printf("events: claim: %v", err)
+			return
+		}
+		for i := range due {
+			e := &due[i]
+			r.relay(ctx, e)
+			if err := r.outbox.Record(e); err != nil {
+				log.Printf("events: record event %d: %v", e.ID, err)
+			}
+		}
+		if len(due) < r.batch || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (r *EventRelay) relay(ctx context.Context, e *models.OutboxEvent) {
+	e.Attempts++
+	err := r.bus.Dispatch(ctx, e)
+	now := time.Now()
+	if err == nil {
+		e.ProcessedAt, e.NextAttemptAt, e.LastError = &now, nil, ""
+		return
+	}
+	e.LastError = err.Error()
+	if e.Attempts >= r.maxAttempts {
+		log.Printf("events: giving up on event %d (%s): %v", e.ID, e.Type, err)
+		e.ProcessedAt, e.NextAttemptAt = &now, nil
+		return
+	}
+	delay := relayMaxDelay
+	if e.Attempts < 20 {
+		if d := relayBaseDelay << (e.Attempts - 1); d < relayMaxDelay {
+			delay = d
+		}
+	}
+	next := now.Add(delay)
+	e.NextAttemptAt = &next
+}
+
+// WelcomeEmail is the event bus subscriber that queues an email greeting
+// newly registered users. The job's unique key keeps a relayed-again
+// event from sending it twice.
+func WelcomeEmail(queue repository.JobRepository) events.Handler {
+	return func(ctx context.Context, e *models.OutboxEvent) error {
+		var user models.User
+		if err := json.Unmarshal(e.Payload, &user); err != nil {
+			return err
+		}
+		_, err := jobs.Enqueue(queue, jobs.TypeNotify, jobs.NotifyArgs{
+			Channel: string(models.ReminderEmail),
+			Message: notify.Message{
+				Kind:   "welcome",
+				UserID: user.ID,
+				Email:  user.Email,
+				Title:  "Welcome to Todo",
+				Body:   fmt.Sprintf("Hi %s,\n\nyour account is ready. Sign in with %s to start adding todos.", user.Name, user.Email),
+			},
+		}, fmt.Sprintf("welcome:%d", user.ID))
+		return err
+	}
+}
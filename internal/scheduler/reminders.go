@@ -0,0 +1,123 @@
+// Package scheduler runs background loops that act on stored schedules.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/jobs"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/notify"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// ReminderScheduler polls for due reminders and queues their delivery as
+// TypeNotify jobs. It is safe to run in every app instance: claiming
+// happens with SKIP LOCKED, so each reminder is queued by exactly one of
+// them. A reminder is marked fired in the transaction that queues its job,
+// and the job's unique key keeps it from being queued twice; sending, and
+// retrying a failed send, is left to the job worker.
+type ReminderScheduler struct {
+	tx       repository.TxManager
+	interval time.Duration
+	batch    int
+}
+
+func NewReminderScheduler(tx repository.TxManager, interval time.Duration, batch int) *ReminderScheduler {
+	return &ReminderScheduler{
+		tx:       tx,
+		interval: interval,
+		batch:    batch,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReminderScheduler) tick(ctx context.Context) {
+	for {
+		n := 0
+		err := s.tx.Do(func(r repository.Repos) error {
+			due, err := r.Reminders.ClaimDue(time.Now(), s.batch)
+			if err != nil {
+				return err
+			}
+			n = len(due)
+			for i := range due {
+				if err := s.queue(r, &due[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("reminders: %v", err)
+			return
+		}
+		// A full batch means more may be waiting; keep draining.
+		if n < s.batch || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// queue enqueues the job delivering rem. A reminder whose todo or user is
+// gone is left fired with the reason in last_error.
+func (s *ReminderScheduler) queue(r repository.Repos, rem *models.Reminder) error {
+	msg, err := reminderMessage(r, rem)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.Reminders.SetError(rem.ID, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	_, err = jobs.Enqueue(r.Jobs, jobs.TypeNotify, jobs.NotifyArgs{
+		Channel: string(rem.Channel),
+		Message: msg,
+	}, fmt.Sprintf("reminder:%d:%d", rem.ID, rem.FireAt.Unix()))
+	return err
+}
+
+func reminderMessage(r repository.Repos, rem *models.Reminder) (notify.Message, error) {
+	todo, err := r.Todos.FindByID(rem.TodoID)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	user, err := r.Users.FindByID(rem.UserID)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	body := todo.Title
+	if todo.DueDate != nil {
+		body = fmt.Sprintf("%s is due %s", todo.Title, todo.DueDate.Format(time.RFC1123))
+	}
+	return notify.Message{
+		Kind:   "reminder",
+		UserID: user.ID,
+		Email:  user.Email,
+		Title:  "Reminder: " + todo.Title,
+		Body:   body,
+		TodoID: todo.ID,
+		Target: rem.Target,
+		Payload: map[string]interface{}{
+			"reminder_id": rem.ID,
+			"due_date":    todo.DueDate,
+		},
+	}, nil
+}
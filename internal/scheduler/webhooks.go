@@ -0,0 +1,183 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/notify"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// Retry delays grow from webhookBaseDelay, doubling per failed attempt up
+// to webhookMaxDelay.
+const (
+	webhookBaseDelay = 30 * time.Second
+	webhookMaxDelay  = 6 * time.Hour
+	// webhookResponseLog is how much of a response body is kept in the log.
+	webhookResponseLog = 1024
+)
+
+// WebhookDispatcher queues a delivery of each relayed event for the
+// webhooks subscribed to it and sends pending deliveries, retrying
+// failures with exponential backoff until they succeed or run out of
+// attempts and go dead. Like the reminder scheduler it is safe to run in
+// every app instance. Webhook URLs are user-supplied, so requests only go
+// to public addresses.
+type WebhookDispatcher struct {
+	webhooks    repository.WebhookRepository
+	client      *http.Client
+	interval    time.Duration
+	batch       int
+	maxAttempts int
+}
+
+func NewWebhookDispatcher(
+	webhooks repository.WebhookRepository,
+	timeout time.Duration,
+	interval time.Duration,
+	batch int,
+	maxAttempts int,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhooks:    webhooks,
+		client:      notify.NewPublicClient(timeout),
+		interval:    interval,
+		batch:       batch,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Enqueue is the event bus subscriber that queues deliveries of an event.
+func (d *WebhookDispatcher) Enqueue(ctx context.Context, e *models.OutboxEvent) error {
+	_, err := d.webhooks.Enqueue(e.ID)
+	return err
+}
+
+func (d *WebhookDispatcher) tick(ctx context.Context) {
+	for {
+		// Claimed deliveries are sent one after another, so the lease
+		// covers the whole batch timing out.
+		lease := time.Duration(d.batch)*d.client.Timeout + time.Minute
+		due, err := d.webhooks.ClaimDue(time.Now(), d.batch, lease)
+		if err != nil {
+			log.Printf("webhooks: claim: %v", err)
+			return
+		}
+		for i := range due {
+			del := &due[i]
+			attempt := d.deliver(ctx, del)
+			if err := d.webhooks.RecordAttempt(del, &attempt); err != nil {
+				log.Printf("webhooks: record delivery %d: %v", del.ID, err)
+			}
+		}
+		if len(due) < d.batch || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt and moves the delivery to its next state.
+func (d *WebhookDispatcher) deliver(ctx context.Context, del *models.WebhookDelivery) models.WebhookAttempt {
+	del.Attempts++
+	w := del.Webhook
+	if w == nil || !w.Active {
+		del.Status, del.NextAttemptAt, del.LastError = models.DeliveryDead, nil, "webhook is disabled"
+		return models.WebhookAttempt{Error: del.LastError}
+	}
+
+	start := time.Now()
+	status, body, err := d.post(ctx, w, del)
+	attempt := models.WebhookAttempt{
+		StatusCode: status,
+		Response:   body,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	del.LastStatusCode = status
+	if err == nil && status >= 200 && status < 300 {
+		now := time.Now()
+		del.Status, del.NextAttemptAt, del.DeliveredAt, del.LastError = models.DeliverySucceeded, nil, &now, ""
+		return attempt
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.Error = fmt.Sprintf("endpoint responded %d", status)
+	}
+	del.LastError = attempt.Error
+	if del.Attempts >= d.maxAttempts {
+		del.Status, del.NextAttemptAt = models.DeliveryDead, nil
+		return attempt
+	}
+	next := time.Now().Add(retryDelay(del.Attempts))
+	del.NextAttemptAt = &next
+	return attempt
+}
+
+func (d *WebhookDispatcher) post(ctx context.Context, w *models.Webhook, del *models.WebhookDelivery) (int, string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(del.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-webhooks/1")
+	req.Header.Set("X-Webhook-Event", del.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(del.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(w.Secret, timestamp, del.Payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLog))
+	return resp.StatusCode, string(bytes.ToValidUTF8(body, nil)), nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret. Receivers recompute it to check that a request
+// is authentic, and reject stale timestamps to stop replays.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay is the wait after the given number of failed attempts, with
+// up to 10% jitter so retries of many deliveries spread out.
+func retryDelay(attempts int) time.Duration {
+	delay := webhookMaxDelay
+	if attempts < 20 {
+		if d := webhookBaseDelay << (attempts - 1); d < webhookMaxDelay {
+			delay = d
+		}
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/10+1))
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+var (
+	ErrForbidden          = errors.New("forbidden")
+	ErrPreconditionFailed = errors.New("precondition failed: todo has changed")
+)
+
+// Actor is the authenticated user performing a request.
+type Actor struct {
+	ID   uint
+	Role models.Role
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.Role == models.RoleAdmin
+}
+
+// AccessControl decides whether an actor may view or edit todos and
+// projects. Owners and admins have full access; everyone else needs a
+// share. Shares are looked up on every call, so revoking one takes effect
+// immediately.
+type AccessControl interface {
+	Todo(todo *models.Todo, actor Actor, need models.Permission) error
+	Project(p *models.Project, actor Actor, need models.Permission) error
+	ProjectByID(projectID uint, actor Actor, need models.Permission) error
+}
+
+type accessControl struct {
+	shares   repository.ShareRepository
+	projects repository.ProjectRepository
+}
+
+func NewAccessControl(shares repository.ShareRepository, projects repository.ProjectRepository) AccessControl {
+	return &accessControl{shares: shares, projects: projects}
+}
+
+func (a *accessControl) Todo(todo *models.Todo, actor Actor, need models.Permission) error {
+	if actor.IsAdmin() || todo.OwnerID == actor.ID {
+		return nil
+	}
+	if todo.ProjectID != nil {
+		p, err := a.projects.FindByID(*todo.ProjectID)
+		if err == nil && p.OwnerID == actor.ID {
+			return nil
+		}
+	}
+	perm, err := a.shares.PermissionFor(actor.ID, todo.ID, todo.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !perm.Allows(need) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (a *accessControl) Project(p *models.Project, actor Actor, need models.Permission) error {
+	if actor.IsAdmin() || p.OwnerID == actor.ID {
+		return nil
+	}
+	perm, err := a.shares.ProjectPermissionFor(actor.ID, p.ID)
+	if err != nil {
+		return err
+	}
+	if !perm.Allows(need) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (a *accessControl) ProjectByID(projectID uint, actor Actor, need models.Permission) error {
+	p, err := a.projects.FindByID(projectID)
+	if err != nil {
+		return err
+	}
+	return a.Project(p, actor, need)
+}
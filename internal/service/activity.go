@@ -0,0 +1,48 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// diffTodo lists the user-visible fields that differ between before and
+// after as activity entries attributed to actorID.
+func diffTodo(before, after *models.Todo, actorID uint) []models.TodoActivity {
+	var out []models.TodoActivity
+	add := func(field, oldV, newV string) {
+		if oldV != newV {
+			out = append(out, models.TodoActivity{
+				TodoID:   after.ID,
+				ActorID:  actorID,
+				Field:    field,
+				OldValue: oldV,
+				NewValue: newV,
+			})
+		}
+	}
+	add("title", before.Title, after.Title)
+	add("description", before.Description, after.Description)
+	add("priority", string(before.Priority), string(after.Priority))
+	add("due_date", formatTime(before.DueDate), formatTime(after.DueDate))
+	add("completed", strconv.FormatBool(before.Completed), strconv.FormatBool(after.Completed))
+	add("auto_complete", strconv.FormatBool(before.AutoComplete), strconv.FormatBool(after.AutoComplete))
+	add("rrule", before.RRule, after.RRule)
+	add("project_id", formatID(before.ProjectID), formatID(after.ProjectID))
+	return out
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
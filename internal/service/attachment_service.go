@@ -0,0 +1,308 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+)
+
+var (
+	ErrFileTooLarge     = errors.New("file too large")
+	ErrQuotaExceeded    = errors.New("attachment quota exceeded")
+	ErrUnsupportedMedia = errors.New("file type not allowed")
+)
+
+// AttachmentLimits bounds what may be uploaded to a single todo.
+type AttachmentLimits struct {
+	MaxBytes     int64
+	MaxPerTodo   int
+	MaxTodoBytes int64
+	AllowedTypes []string // media types; "image/*" matches a whole family
+}
+
+func AttachmentLimitsFrom(cfg *config.Config) AttachmentLimits {
+	return AttachmentLimits{
+		MaxBytes:     cfg.AttachmentMaxBytes,
+		MaxPerTodo:   cfg.AttachmentMaxPerTodo,
+		MaxTodoBytes: cfg.AttachmentMaxTodoBytes,
+		AllowedTypes: cfg.AttachmentAllowedTypes,
+	}
+}
+
+type AttachmentService interface {
+	List(todoID uint, actor Actor) ([]models.Attachment, error)
+	Upload(todoID uint, filename string, size int64, r io.Reader, actor Actor) (*models.Attachment, error)
+	Open(todoID, id uint, actor Actor) (*models.Attachment, io.ReadCloser, error)
+	Delete(todoID, id uint, actor Actor) error
+}
+
+type attachmentService struct {
+	repo   repository.AttachmentRepository
+	todos  repository.TodoRepository
+	blobs  storage.BlobStore
+	access AccessControl
+	limits AttachmentLimits
+	urls   *storage.URLSigner
+	tx     repository.TxManager
+}
+
+func NewAttachmentService(
+	r repository.AttachmentRepository,
+	todos repository.TodoRepository,
+	blobs storage.BlobStore,
+	access AccessControl,
+	limits AttachmentLimits,
+	urls *storage.URLSigner,
+	tx repository.TxManager,
+) AttachmentService {
+	return &attachmentService{repo: r, todos: todos, blobs: blobs, access: access, limits: limits, urls: urls, tx: tx}
+}
+
+func (s *attachmentService) List(todoID uint, actor Actor) ([]models.Attachment, error) {
+	if err := s.authorize(todoID, actor, models.PermissionViewer); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindByTodo(todoID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		s.sign(&items[i])
+	}
+	return items, nil
+}
+
+// Upload stores a new file on the todo. The content type is sniffed from
+// the leading bytes rather than trusted from the client.
+//
+// The quota is checked up front to refuse early, and again when the row is
+// inserted, in one transaction holding a lock on the todo's row, so
+// concurrent uploads cannot together exceed it. The file is stored
+// between the two checks and removed again if the second one fails.
+func (s *attachmentService) Upload(todoID uint, filename string, size int64, r io.Reader, actor Actor) (*models.Attachment, error) {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Todo(todo, actor, models.PermissionEditor); err != nil {
+		return nil, err
+	}
+	filename = cleanFilename(filename)
+	if filename == "" {
+		return nil, errors.New("filename is required")
+	}
+	if size <= 0 {
+		return nil, errors.New("file is empty")
+	}
+	if s.limits.MaxBytes > 0 && size > s.limits.MaxBytes {
+		return nil, fmt.Errorf("%w (max %d bytes)", ErrFileTooLarge, s.limits.MaxBytes)
+	}
+	if err := s.checkQuota(s.repo, todoID, size); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := sniffType(head, filename)
+	if !typeAllowed(contentType, s.limits.AllowedTypes) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMedia, contentType)
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("attachments/%d", todoID), filename)
+	if err != nil {
+		return nil, err
+	}
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if err := s.blobs.Put(context.Background(), key, body, size, contentType); err != nil {
+		return nil, err
+	}
+	a := &models.Attachment{
+		TodoID:      todoID,
+		UploaderID:  actor.ID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	err = s.tx.Do(func(r repository.Repos) error {
+		if err := r.Todos.Lock(todoID); err != nil {
+			return err
+		}
+		if err := s.checkQuota(r.Attachments, todoID, size); err != nil {
+			return err
+		}
+		return r.Attachments.Create(a)
+	})
+	if err != nil {
+		removeBlobs(s.blobs, key)
+		return nil, err
+	}
+	s.sign(a)
+	return a, nil
+}
+
+// checkQuota fails with ErrQuotaExceeded if adding a file of size bytes
+// would take the todo past its limits.
+func (s *attachmentService) checkQuota(repo repository.AttachmentRepository, todoID uint, size int64) error {
+	count, used, err := repo.Usage(todoID)
+	if err != nil {
+		return err
+	}
+	if s.limits.MaxPerTodo > 0 && count >= int64(s.limits.MaxPerTodo) {
+		return fmt.Errorf("%w: at most %d files per todo", ErrQuotaExceeded, s.limits.MaxPerTodo)
+	}
+	if s.limits.MaxTodoBytes > 0 && used+size > s.limits.MaxTodoBytes {
+		return fmt.Errorf("%w: at most %d bytes per todo", ErrQuotaExceeded, s.limits.MaxTodoBytes)
+	}
+	return nil
+}
+
+// Open returns the attachment together with a reader of its contents. The
+// caller must close the reader.
+func (s *attachmentService) Open(todoID, id uint, actor Actor) (*models.Attachment, io.ReadCloser, error) {
+	if err := s.authorize(todoID, actor, models.PermissionViewer); err != nil {
+		return nil, nil, err
+	}
+	a, err := s.repo.FindByID(todoID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, _, err := s.blobs.Get(context.Background(), a.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, rc, nil
+}
+
+// Delete removes an attachment. The uploader may always remove their own
+// files; anyone else needs editor access.
+func (s *attachmentService) Delete(todoID, id uint, actor Actor) error {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return err
+	}
+	if err := s.access.Todo(todo, actor, models.PermissionViewer); err != nil {
+		return err
+	}
+	a, err := s.repo.FindByID(todoID, id)
+	if err != nil {
+		return err
+	}
+	if a.UploaderID != actor.ID {
+		if err := s.access.Todo(todo, actor, models.PermissionEditor); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Delete(todoID, id); err != nil {
+		return err
+	}
+	removeBlobs(s.blobs, a.StorageKey)
+	return nil
+}
+
+// sign sets a direct download URL on a. Without URL signing attachments
+// are only available through the download endpoint.
+func (s *attachmentService) sign(a *models.Attachment) {
+	if s.urls.Enabled() {
+		a.URL = s.urls.URL(a.StorageKey, a.Filename)
+	}
+}
+
+func (s *attachmentService) authorize(todoID uint, actor Actor, need models.Permission) error {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return err
+	}
+	return s.access.Todo(todo, actor, need)
+}
+
+// removeBlobs deletes files whose metadata rows are already gone. Failures
+// only leave orphaned files behind, so they are logged rather than returned.
+func removeBlobs(blobs storage.BlobStore, keys ...string) {
+	for _, key := range keys {
+		if err := blobs.Delete(context.Background(), key); err != nil {
+			log.Printf("storage: delete %s: %v", key, err)
+		}
+	}
+}
+
+// newStorageKey returns a random, unguessable key under prefix that keeps
+// the original file extension.
+func newStorageKey(prefix, filename string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if len(ext) > 10 || strings.ContainsAny(ext, "/\\ ") {
+		ext = ""
+	}
+	return prefix + "/" + hex.EncodeToString(b) + ext, nil
+}
+
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return strings.TrimSpace(name)
+}
+
+// sniffType detects the media type of a file from its first bytes. Plain
+// text is refined by extension so CSV or Markdown keep their own type.
+func sniffType(head []byte, filename string) string {
+	mt, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if mt == "text/plain" {
+		if byExt, _, err := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(filename))); err == nil && strings.HasPrefix(byExt, "text/") {
+			return byExt
+		}
+	}
+	return mt
+}
+
+func typeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		switch {
+		case pattern == "*" || pattern == "*/*":
+			return true
+		case strings.HasSuffix(pattern, "/*"):
+			if strings.HasPrefix(contentType, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		case pattern == contentType:
+			return true
+		}
+	}
+	return false
+}
@@ -8,22 +8,27 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/events"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
 )
 
 type AuthService interface {
 	Register(name, email, password string, role models.Role) (*models.User, error)
 	Login(email, password string) (string, *models.User, error)
+	Authenticate(email, password string) (*models.User, error)
 }
 
 type authService struct {
 	cfg  *config.Config
 	repo repository.UserRepository
+	tx   repository.TxManager
+	urls *storage.URLSigner
 }
 
-func NewAuthService(cfg *config.Config, r repository.UserRepository) AuthService {
-	return &authService{cfg: cfg, repo: r}
+func NewAuthService(cfg *config.Config, r repository.UserRepository, tx repository.TxManager, urls *storage.URLSigner) AuthService {
+	return &authService{cfg: cfg, repo: r, tx: tx, urls: urls}
 }
 
 func (s *authService) Register(name, email, password string, role models.Role) (*models.User, error) {
@@ -37,19 +42,34 @@ func (s *authService) Register(name, email, password string, role models.Role) (
 		PasswordHash: string(hash),
 		Role:         role,
 	}
-	if err := s.repo.Create(user); err != nil {
+	err = s.tx.Do(func(r repository.Repos) error {
+		if err := r.Users.Create(user); err != nil {
+			return err
+		}
+		return publish(r.Outbox, events.UserRegistered{User: user})
+	})
+	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *authService) Login(email, password string) (string, *models.User, error) {
+// Authenticate checks an email and password without issuing a token.
+func (s *authService) Authenticate(email, password string) (*models.User, error) {
 	user, err := s.repo.FindByEmail(email)
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
+	}
+	return user, nil
+}
+
+func (s *authService) Login(email, password string) (string, *models.User, error) {
+	user, err := s.Authenticate(email, password)
+	if err != nil {
+		return "", nil, err
 	}
 
 	claims := jwt.MapClaims{
@@ -64,5 +84,6 @@ func (s *authService) Login(email, password string) (string, *models.User, error
 	if err != nil {
 		return "", nil, err
 	}
+	signUser(s.urls, user)
 	return signed, user, nil
 }
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/ical"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+)
+
+// CalDAV errors, mapped to WebDAV preconditions by the handler.
+var (
+	ErrInvalidSyncToken     = errors.New("invalid or expired sync token")
+	ErrUnsupportedComponent = errors.New("only VTODO resources are supported")
+)
+
+// syncTokenPrefix starts every sync token; tokens must be URIs (RFC 6578).
+const syncTokenPrefix = "urn:x-todo-sync:"
+
+// CalDAVService exposes the actor's own todos as a CalDAV task
+// collection. Resources are named after the todo's UID (todoio.UID); todos
+// created by clients keep the resource name as their external ID. Writes
+// go through TodoService, so validation, activity and recurrence behave
+// exactly as in the REST API.
+type CalDAVService interface {
+	SyncToken(actor Actor) (string, error)
+	List(actor Actor) ([]models.Todo, string, error)
+	Changes(token string, actor Actor) ([]models.Todo, string, error)
+	Get(name string, actor Actor) (*models.Todo, error)
+	Put(name string, data []byte, ifMatch string, ifNoneMatch string, actor Actor) (*models.Todo, bool, error)
+	Delete(name string, ifMatch string, actor Actor) error
+}
+
+type caldavService struct {
+	repo  repository.TodoRepository
+	users repository.UserRepository
+	todos TodoService
+}
+
+func NewCalDAVService(r repository.TodoRepository, users repository.UserRepository, todos TodoService) CalDAVService {
+	return &caldavService{repo: r, users: users, todos: todos}
+}
+
+// DavETag is the entity tag of a todo resource.
+func DavETag(t *models.Todo) string {
+	return fmt.Sprintf(`"%d-%d"`, t.ID, t.Version)
+}
+
+// CalendarData renders a todo as a one-VTODO calendar object.
+func CalendarData(t *models.Todo) ([]byte, error) {
+	cal := todoio.NewCalendar("")
+	cal.Components = append(cal.Components, todoio.VTodo(t, todoio.UID(t)))
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SyncToken encodes the collection state: the latest modification and
+// the number of todos. Comparing counts is what reveals deletions.
+func (s *caldavService) SyncToken(actor Actor) (string, error) {
+	state, err := s.repo.SyncState(actor.ID)
+	if err != nil {
+		return "", err
+	}
+	return formatSyncToken(state), nil
+}
+
+func (s *caldavService) List(actor Actor) ([]models.Todo, string, error) {
+	token, err := s.SyncToken(actor)
+	if err != nil {
+		return nil, "", err
+	}
+	todos, err := s.repo.FindUpdatedSince(actor.ID, time.Time{})
+	if err != nil {
+		return nil, "", err
+	}
+	return todos, token, nil
+}
+
+// Changes returns the todos modified since token. Deleted todos leave no
+// trace, so when any todo known at token is gone the token is rejected and
+// the client falls back to a full sync.
+func (s *caldavService) Changes(token string, actor Actor) ([]models.Todo, string, error) {
+	if token == "" {
+		return s.List(actor)
+	}
+	since, count, ok := parseSyncToken(token)
+	if !ok {
+		return nil, "", ErrInvalidSyncToken
+	}
+	state, err := s.repo.SyncState(actor.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	changed, err := s.repo.FindUpdatedSince(actor.ID, since)
+	if err != nil {
+		return nil, "", err
+	}
+	var created int64
+	for _, t := range changed {
+		if t.CreatedAt.After(since) {
+			created++
+		}
+	}
+	if state.Count-created < count {
+		return nil, "", ErrInvalidSyncToken
+	}
+	return changed, formatSyncToken(state), nil
+}
+
+func (s *caldavService) Get(name string, actor Actor) (*models.Todo, error) {
+	return s.repo.FindByExternalID(actor.ID, name)
+}
+
+// Put creates or replaces the todo stored under name from an iCalendar
+// object in a single transaction. ifMatch and ifNoneMatch are the raw
+// precondition headers. It reports whether the todo was created.
+func (s *caldavService) Put(name string, data []byte, ifMatch string, ifNoneMatch string, actor Actor) (*models.Todo, bool, error) {
+	cal, err := ical.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, err
+	}
+	vtodos := cal.Children("VTODO")
+	if cal.Name != "VCALENDAR" || len(vtodos) != 1 {
+		return nil, false, ErrUnsupportedComponent
+	}
+	input, tags, err := todoio.FromVTodo(vtodos[0], userLocation(s.users, actor.ID))
+	if err != nil {
+		return nil, false, err
+	}
+	if tags, err = normalizeTags(tags); err != nil {
+		return nil, false, err
+	}
+
+	var todo *models.Todo
+	var created bool
+	err = s.todos.InTx(func(todos TodoService, r repository.Repos) error {
+		todo, created, err = s.put(todos, r, name, &input, tags, ifMatch, ifNoneMatch, actor)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return todo, created, nil
+}
+
+// put does the writes of Put inside its transaction, so a failure part
+// way leaves nothing behind for the client's retry to trip over.
+func (s *caldavService) put(todos TodoService, r repository.Repos, name string, input *models.Todo, tags []string, ifMatch, ifNoneMatch string, actor Actor) (*models.Todo, bool, error) {
+	existing, err := r.Todos.FindByExternalID(actor.ID, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	switch {
+	case existing != nil && strings.TrimSpace(ifNoneMatch) == "*":
+		return nil, false, ErrPreconditionFailed
+	case ifMatch != "" && (existing == nil || !davETagMatches(ifMatch, existing)):
+		return nil, false, ErrPreconditionFailed
+	}
+
+	if existing == nil {
+		ext := name
+		input.ExternalID = &ext
+		todo, err := todos.Create(input, actor)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := setTags(r.Tags, todo, tags); err != nil {
+			return nil, false, err
+		}
+		todo, err = r.Todos.FindByID(todo.ID)
+		return todo, true, err
+	}
+
+	// A change of completion goes through the same path as on the toggle
+	// endpoint, so recurring todos spawn their next occurrence and
+	// checklists follow.
+	if input.ProjectID == nil {
+		input.ProjectID = existing.ProjectID
+	}
+	input.AutoComplete = existing.AutoComplete
+	version := existing.Version
+	todo, err := todos.Update(existing.ID, input, &version, actor)
+	if err != nil {
+		return nil, false, err
+	}
+	todo.Tags = existing.Tags
+	if err := setTags(r.Tags, todo, tags); err != nil {
+		return nil, false, err
+	}
+	todo, err = r.Todos.FindByID(todo.ID)
+	return todo, false, err
+}
+
+// Delete removes a todo. As everywhere else, deleting is reserved to
+// admins; other users get ErrForbidden.
+func (s *caldavService) Delete(name string, ifMatch string, actor Actor) error {
+	existing, err := s.repo.FindByExternalID(actor.ID, name)
+	if err != nil {
+		return err
+	}
+	if ifMatch != "" && !davETagMatches(ifMatch, existing) {
+		return ErrPreconditionFailed
+	}
+	return s.todos.Delete(existing.ID, actor)
+}
+
+// setTags makes the todo's tags exactly names.
+func setTags(repo repository.TagRepository, todo *models.Todo, names []string) error {
+	want := map[string]bool{}
+	for _, n := range names {
+		want[n] = true
+	}
+	var stale []models.Tag
+	for _, t := range todo.Tags {
+		if !want[t.Name] {
+			stale = append(stale, t)
+		}
+		delete(want, t.Name)
+	}
+	if err := repo.Detach(todo.ID, stale); err != nil {
+		return err
+	}
+	missing := make([]string, 0, len(want))
+	for n := range want {
+		missing = append(missing, n)
+	}
+	tags, err := repo.FindOrCreate(missing)
+	if err != nil {
+		return err
+	}
+	return repo.Attach(todo.ID, tags)
+}
+
+func davETagMatches(header string, t *models.Todo) bool {
+	want := DavETag(t)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == want {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSyncToken(state *repository.SyncState) string {
+	var micros int64
+	if !state.Latest.IsZero() {
+		micros = state.Latest.UnixMicro()
+	}
+	return fmt.Sprintf("%s%d-%d", syncTokenPrefix, micros, state.Count)
+}
+
+func parseSyncToken(token string) (time.Time, int64, bool) {
+	rest, ok := strings.CutPrefix(token, syncTokenPrefix)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	a, b, ok := strings.Cut(rest, "-")
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	micros, err1 := strconv.ParseInt(a, 10, 64)
+	count, err2 := strconv.ParseInt(b, 10, 64)
+	if err1 != nil || err2 != nil || micros < 0 || count < 0 {
+		return time.Time{}, 0, false
+	}
+	if micros == 0 {
+		return time.Time{}, count, true
+	}
+	return time.UnixMicro(micros), count, true
+}
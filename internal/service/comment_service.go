@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+)
+
+type CommentService interface {
+	List(todoID uint, actor Actor) ([]models.Comment, error)
+	Create(todoID uint, body string, actor Actor) (*models.Comment, error)
+	Update(todoID, id uint, body string, actor Actor) (*models.Comment, error)
+	Delete(todoID, id uint, actor Actor) error
+	Activity(todoID uint, limit int, actor Actor) ([]models.ActivityEntry, error)
+}
+
+type commentService struct {
+	repo          repository.CommentRepository
+	activity      repository.ActivityRepository
+	todos         repository.TodoRepository
+	users         repository.UserRepository
+	access        AccessControl
+	notifications NotificationService
+	urls          *storage.URLSigner
+	validator     *validator.Validate
+}
+
+func NewCommentService(
+	r repository.CommentRepository,
+	activity repository.ActivityRepository,
+	todos repository.TodoRepository,
+	users repository.UserRepository,
+	access AccessControl,
+	notifications NotificationService,
+	urls *storage.URLSigner,
+) CommentService {
+	return &commentService{
+		repo:          r,
+		activity:      activity,
+		todos:         todos,
+		users:         users,
+		access:        access,
+		notifications: notifications,
+		urls:          urls,
+		validator:     validator.New(),
+	}
+}
+
+func (s *commentService) List(todoID uint, actor Actor) ([]models.Comment, error) {
+	if _, err := s.todo(todoID, actor); err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.FindByTodo(todoID, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Oldest first reads naturally as a thread.
+	for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+		comments[i], comments[j] = comments[j], comments[i]
+	}
+	for i := range comments {
+		signUser(s.urls, comments[i].Author)
+	}
+	return comments, nil
+}
+
+// Create adds a comment. Anyone who can view the todo may comment; the todo
+// owner and every mentioned user who can see the todo are notified.
+func (s *commentService) Create(todoID uint, body string, actor Actor) (*models.Comment, error) {
+	todo, err := s.todo(todoID, actor)
+	if err != nil {
+		return nil, err
+	}
+	c := &models.Comment{TodoID: todoID, AuthorID: actor.ID, Body: strings.TrimSpace(body)}
+	if err := s.validator.Struct(c); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(c); err != nil {
+		return nil, err
+	}
+	author, _ := s.users.FindByID(actor.ID)
+	c.Author = author
+	c.Mentions = s.notifyMentions(todo, c, author)
+	if todo.OwnerID != actor.ID && !containsID(c.Mentions, todo.OwnerID) {
+		title := fmt.Sprintf("%s commented on %s", displayName(author), todo.Title)
+		_, _ = s.notifications.Notify(todo.OwnerID, models.NotificationComment, title, excerpt(c.Body), &todo.ID)
+	}
+	signUser(s.urls, c.Author)
+	return c, nil
+}
+
+// Update edits a comment; only its author may do so. Users mentioned for
+// the first time are notified.
+func (s *commentService) Update(todoID, id uint, body string, actor Actor) (*models.Comment, error) {
+	todo, err := s.todo(todoID, actor)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.repo.FindByID(todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.AuthorID != actor.ID {
+		return nil, ErrForbidden
+	}
+	previous := map[string]bool{}
+	for _, email := range parseMentions(c.Body) {
+		previous[email] = true
+	}
+	now := time.Now()
+	c.Body = strings.TrimSpace(body)
+	c.EditedAt = &now
+	if err := s.validator.Struct(c); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(c); err != nil {
+		return nil, err
+	}
+	c.Mentions = s.notifyMentionsExcept(todo, c, c.Author, previous)
+	signUser(s.urls, c.Author)
+	return c, nil
+}
+
+// Delete removes a comment. Authors can delete their own comments and
+// admins can delete any comment for moderation.
+func (s *commentService) Delete(todoID, id uint, actor Actor) error {
+	if _, err := s.todo(todoID, actor); err != nil {
+		return err
+	}
+	c, err := s.repo.FindByID(todoID, id)
+	if err != nil {
+		return err
+	}
+	if c.AuthorID != actor.ID && !actor.IsAdmin() {
+		return ErrForbidden
+	}
+	return s.repo.Delete(todoID, id)
+}
+
+// Activity interleaves comments and field changes, newest first.
+func (s *commentService) Activity(todoID uint, limit int, actor Actor) ([]models.ActivityEntry, error) {
+	if _, err := s.todo(todoID, actor); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	comments, err := s.repo.FindByTodo(todoID, limit)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.activity.FindByTodo(todoID, limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]models.ActivityEntry, 0, len(comments)+len(changes))
+	for i := range comments {
+		c := &comments[i]
+		signUser(s.urls, c.Author)
+		entries = append(entries, models.ActivityEntry{Type: "comment", At: c.CreatedAt, ActorID: c.AuthorID, Comment: c})
+	}
+	for i := range changes {
+		ch := &changes[i]
+		entries = append(entries, models.ActivityEntry{Type: "change", At: ch.CreatedAt, ActorID: ch.ActorID, Change: ch})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.After(entries[j].At) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (s *commentService) todo(todoID uint, actor Actor) (*models.Todo, error) {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Todo(todo, actor, models.PermissionViewer); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+func (s *commentService) notifyMentions(todo *models.Todo, c *models.Comment, author *models.User) []uint {
+	return s.notifyMentionsExcept(todo, c, author, nil)
+}
+
+// notifyMentionsExcept resolves @mentions to users who can view the todo,
+// notifies those whose email is not in skip and returns all resolved ids.
+// Mentions of unknown users or of people without access are ignored.
+func (s *commentService) notifyMentionsExcept(todo *models.Todo, c *models.Comment, author *models.User, skip map[string]bool) []uint {
+	var ids []uint
+	for _, email := range parseMentions(c.Body) {
+		u, err := s.users.FindByEmail(email)
+		if err != nil || u.ID == c.AuthorID {
+			continue
+		}
+		if s.access.Todo(todo, Actor{ID: u.ID, Role: u.Role}, models.PermissionViewer) != nil {
+			continue
+		}
+		ids = append(ids, u.ID)
+		if skip[email] {
+			continue
+		}
+		title := fmt.Sprintf("%s mentioned you on %s", displayName(author), todo.Title)
+		_, _ = s.notifications.Notify(u.ID, models.NotificationMention, title, excerpt(c.Body), &todo.ID)
+	}
+	return ids
+}
+
+func displayName(u *models.User) string {
+	if u == nil {
+		return "Someone"
+	}
+	return u.Name
+}
+
+func excerpt(body string) string {
+	const max = 280
+	runes := []rune(body)
+	if len(runes) <= max {
+		return body
+	}
+	return string(runes[:max]) + "…"
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// MaxEventBatch is how many events After returns at once.
+const MaxEventBatch = 200
+
+// EventService reads the recorded todo events of a user for streaming. A
+// client resumes by passing the ID of the last event it saw. After holds
+// back events while a transaction that may still record an earlier one is
+// open, at most repository.MaxHoldBack, and reports pending until it can
+// return them.
+type EventService interface {
+	Latest() (uint, error)
+	After(afterID uint, actor Actor) (events []models.OutboxEvent, pending bool, err error)
+}
+
+type eventService struct {
+	outbox repository.OutboxRepository
+}
+
+func NewEventService(outbox repository.OutboxRepository) EventService {
+	return &eventService{outbox: outbox}
+}
+
+func (s *eventService) Latest() (uint, error) {
+	return s.outbox.LastID()
+}
+
+func (s *eventService) After(afterID uint, actor Actor) ([]models.OutboxEvent, bool, error) {
+	return s.outbox.FindAfter(actor.ID, afterID, "todo.", MaxEventBatch)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/events"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// publish records domain events in the outbox. Writes publish through
+// repositories bound to their transaction, so the events commit or roll
+// back with the change; the relay dispatches them afterwards.
+func publish(outbox repository.OutboxRepository, evs ...events.Event) error {
+	rows, err := events.Record(evs...)
+	if err != nil {
+		return err
+	}
+	return outbox.Add(rows...)
+}
+
+func (s *todoService) publish(evs ...events.Event) error {
+	return publish(s.outbox, evs...)
+}
+
+// publishChange records TodoUpdated for a changed todo, plus
+// TodoCompleted when the change completed it.
+func (s *todoService) publishChange(before, after *models.Todo) error {
+	evs := []events.Event{events.TodoUpdated{Todo: after}}
+	if after.Completed && !before.Completed {
+		evs = append(evs, events.TodoCompleted{Todo: after})
+	}
+	return s.publish(evs...)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/filter"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/ical"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+)
+
+// MaxFeedTodos caps a calendar feed to the todos with the latest due
+// dates.
+const MaxFeedTodos = 1000
+
+// FeedKind selects the components emitted for each todo.
+type FeedKind string
+
+const (
+	FeedAll    FeedKind = "all"   // VTODO and VEVENT
+	FeedTodos  FeedKind = "todo"  // VTODO only (task apps)
+	FeedEvents FeedKind = "event" // VEVENT only (calendars without tasks)
+)
+
+// Feed is a rendered calendar with a validator for conditional requests.
+type Feed struct {
+	Body []byte
+	ETag string
+}
+
+type FeedService interface {
+	Rotate(userID uint) (string, error)
+	Revoke(userID uint) error
+	Calendar(token string, kind FeedKind) (*Feed, error)
+}
+
+type feedService struct {
+	users repository.UserRepository
+	todos repository.TodoRepository
+}
+
+func NewFeedService(users repository.UserRepository, todos repository.TodoRepository) FeedService {
+	return &feedService{users: users, todos: todos}
+}
+
+// Rotate issues a new feed token, invalidating the previous one. Only a
+// hash of the token is stored, so the token is shown this once.
+func (s *feedService) Rotate(userID uint) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	hash := hashFeedToken(token)
+	if err := s.users.SetFeedToken(userID, &hash); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *feedService) Revoke(userID uint) error {
+	return s.users.SetFeedToken(userID, nil)
+}
+
+// Calendar renders the feed of the user owning token: every todo with a
+// due date that the user owns or that is shared with them. The output
+// only depends on the todos (DTSTAMP comes from UpdatedAt), so an
+// unchanged feed keeps its ETag.
+func (s *feedService) Calendar(token string, kind FeedKind) (*Feed, error) {
+	u, err := s.users.FindByFeedToken(hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	hasDue, err := filter.Parse("due_date is not null", repository.TodoFilterFields)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.todos.FindAll(repository.TodoFilter{
+		Sort:          "due_desc",
+		OwnerID:       &u.ID,
+		IncludeShared: true,
+		Where:         hasDue,
+	}, repository.PageRequest{Limit: MaxFeedTodos})
+	if err != nil {
+		return nil, err
+	}
+
+	cal := todoio.NewCalendar("Todos - " + u.Name)
+	if u.Timezone != "" {
+		cal.Add("X-WR-TIMEZONE", u.Timezone)
+	}
+	cal.Add("REFRESH-INTERVAL", "PT1H", "VALUE", "DURATION")
+	cal.Add("X-PUBLISHED-TTL", "PT1H")
+	for i := range page.Todos {
+		t := &page.Todos[i]
+		uid := todoio.UID(t)
+		if kind != FeedEvents {
+			cal.Components = append(cal.Components, todoio.VTodo(t, uid))
+		}
+		if kind != FeedTodos {
+			cal.Components = append(cal.Components, todoio.VEvent(t, uid+"-due"))
+		}
+	}
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return &Feed{Body: buf.Bytes(), ETag: fmt.Sprintf(`"%x"`, sum[:16])}, nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/importer"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/jobs"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// maxImportJobs is how many recent jobs List returns.
+const maxImportJobs = 50
+
+// ImportReport is the outcome of importing another tool's export: the
+// per-row result of the todo import plus what the export contained that
+// could not be imported at all.
+type ImportReport struct {
+	Source importer.Source `json:"source"`
+	ImportResult
+	Skipped []importer.Skipped `json:"skipped"`
+}
+
+// JobImport is the background job type that runs an import job.
+const JobImport = "import.run"
+
+// ImportJobArgs is the payload of a JobImport job.
+type ImportJobArgs struct {
+	ImportJobID uint `json:"import_job_id"`
+}
+
+// ImportJobService imports Todoist, Trello and Microsoft To Do exports,
+// either in the background (Start, then RunJob on a worker) or inline
+// (Run, used by the CLI).
+type ImportJobService interface {
+	Start(source importer.Source, fileName string, data []byte, dryRun bool, actor Actor) (*models.ImportJob, error)
+	Get(id uint, actor Actor) (*models.ImportJob, error)
+	List(actor Actor) ([]models.ImportJob, error)
+	Run(source importer.Source, fileName string, data []byte, dryRun bool, actor Actor) (*ImportReport, error)
+	RunJob(ctx context.Context, args ImportJobArgs) error
+}
+
+type importJobService struct {
+	repo  repository.ImportJobRepository
+	users repository.UserRepository
+	todos TodoService
+	tx    repository.TxManager
+	lease time.Duration
+}
+
+// NewImportJobService runs import jobs; lease is how long a running import
+// job is presumed alive, the job queue's stale timeout.
+func NewImportJobService(r repository.ImportJobRepository, users repository.UserRepository, todos TodoService, tx repository.TxManager, lease time.Duration) ImportJobService {
+	return &importJobService{repo: r, users: users, todos: todos, tx: tx, lease: lease}
+}
+
+// Start checks that the export can be read, records an import job and
+// queues a background job to run it, so the import survives restarts.
+func (s *importJobService) Start(source importer.Source, fileName string, data []byte, dryRun bool, actor Actor) (*models.ImportJob, error) {
+	if _, err := importer.Parse(source, fileName, data, userLocation(s.users, actor.ID)); err != nil {
+		return nil, err
+	}
+	job := &models.ImportJob{
+		OwnerID:  actor.ID,
+		Source:   string(source),
+		FileName: fileName,
+		DryRun:   dryRun,
+		Status:   models.ImportJobQueued,
+		Payload:  data,
+	}
+	err := s.tx.Do(func(r repository.Repos) error {
+		if err := r.ImportJobs.Create(job); err != nil {
+			return err
+		}
+		_, err := jobs.Enqueue(r.Jobs, JobImport, ImportJobArgs{ImportJobID: job.ID}, fmt.Sprintf("import:%d", job.ID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = nil
+	return job, nil
+}
+
+// RunJob imports a queued import job. A job that already finished is left
+// alone, so the background job may safely run again, and so is one that is
+// running, unless it started longer than the lease ago and its run is
+// presumed dead. The import's own failures are recorded on the import job;
+// only storage errors are returned for the job to be retried.
+func (s *importJobService) RunJob(ctx context.Context, args ImportJobArgs) error {
+	job, err := s.repo.FindWithPayload(args.ImportJobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.ImportJobSucceeded || job.Status == models.ImportJobFailed {
+		return nil
+	}
+	owner, err := s.users.FindByID(job.OwnerID)
+	if err != nil {
+		return err
+	}
+	actor := Actor{ID: owner.ID, Role: owner.Role}
+
+	now := time.Now()
+	started, err := s.repo.Start(job.ID, now, now.Add(-s.lease))
+	if err != nil {
+		return err
+	}
+	if !started {
+		return nil
+	}
+	job.Status, job.StartedAt = models.ImportJobRunning, &now
+	report, err := s.Run(importer.Source(job.Source), job.FileName, job.Payload, job.DryRun, actor)
+	if err == nil {
+		job.Report, err = json.Marshal(report)
+	}
+	done := time.Now()
+	job.FinishedAt, job.Payload = &done, nil
+	if err != nil {
+		job.Status, job.Error = models.ImportJobFailed, err.Error()
+	} else {
+		job.Status = models.ImportJobSucceeded
+	}
+	return s.repo.Update(job)
+}
+
+func (s *importJobService) Get(id uint, actor Actor) (*models.ImportJob, error) {
+	job, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.OwnerID != actor.ID && !actor.IsAdmin() {
+		return nil, ErrForbidden
+	}
+	return job, nil
+}
+
+func (s *importJobService) List(actor Actor) ([]models.ImportJob, error) {
+	return s.repo.FindByOwner(actor.ID, maxImportJobs)
+}
+
+// Run imports an export right away.
+func (s *importJobService) Run(source importer.Source, fileName string, data []byte, dryRun bool, actor Actor) (*ImportReport, error) {
+	parsed, err := importer.Parse(source, fileName, data, userLocation(s.users, actor.ID))
+	if err != nil {
+		return nil, err
+	}
+	return s.importParsed(source, parsed, dryRun, actor)
+}
+
+func (s *importJobService) importParsed(source importer.Source, parsed *importer.Result, dryRun bool, actor Actor) (*ImportReport, error) {
+	result, err := s.todos.Import(parsed.Records, dryRun, actor)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+	skipped := parsed.Skipped
+	if skipped == nil {
+		skipped = []importer.Skipped{}
+	}
+	return &ImportReport{Source: source, ImportResult: *result, Skipped: skipped}, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// MaxJobPage caps a job listing.
+const MaxJobPage = 100
+
+var ErrJobNotFailed = errors.New("only failed jobs can be retried")
+
+// JobList is a page of jobs with the number of jobs in each status.
+type JobList struct {
+	Counts map[models.JobStatus]int64 `json:"counts"`
+	Jobs   []models.Job               `json:"jobs"`
+}
+
+// JobService lets admins inspect the background job queue. Jobs are run
+// by jobs.Worker.
+type JobService interface {
+	List(status models.JobStatus, typ string, beforeID uint, limit int) (*JobList, error)
+	Get(id uint) (*models.Job, error)
+	Retry(id uint) (*models.Job, error)
+}
+
+type jobService struct {
+	repo repository.JobRepository
+}
+
+func NewJobService(r repository.JobRepository) JobService {
+	return &jobService{repo: r}
+}
+
+func (s *jobService) List(status models.JobStatus, typ string, beforeID uint, limit int) (*JobList, error) {
+	switch status {
+	case "", models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobFailed:
+	default:
+		return nil, fmt.Errorf("unknown status %q", status)
+	}
+	if limit <= 0 || limit > MaxJobPage {
+		limit = MaxJobPage
+	}
+	items, err := s.repo.FindAll(status, typ, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &JobList{Counts: counts, Jobs: items}, nil
+}
+
+func (s *jobService) Get(id uint) (*models.Job, error) {
+	return s.repo.FindByID(id)
+}
+
+// Retry queues a failed job to run again right away with a fresh set of
+// attempts.
+func (s *jobService) Retry(id uint) (*models.Job, error) {
+	job, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.JobFailed {
+		return nil, ErrJobNotFailed
+	}
+	if err := s.repo.Retry(id, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(id)
+}
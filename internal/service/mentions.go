@@ -0,0 +1,30 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fencedCode = regexp.MustCompile("(?s)```.*?```")
+	inlineCode = regexp.MustCompile("`[^`\n]*`")
+	// A mention is "@" followed by the user's email, e.g. @jane@example.com.
+	mentionRe = regexp.MustCompile(`(?:^|[^\w.+-])@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+)
+
+// parseMentions returns the distinct emails mentioned in a Markdown body,
+// ignoring anything inside code spans and fenced blocks.
+func parseMentions(body string) []string {
+	body = fencedCode.ReplaceAllString(body, " ")
+	body = inlineCode.ReplaceAllString(body, " ")
+	seen := map[string]bool{}
+	var out []string
+	for _, m := range mentionRe.FindAllStringSubmatch(body, -1) {
+		email := strings.TrimRight(m[1], ".")
+		if key := strings.ToLower(email); !seen[key] {
+			seen[key] = true
+			out = append(out, email)
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+type NotificationService interface {
+	Notify(userID uint, kind models.NotificationType, title, body string, todoID *uint) (*models.Notification, error)
+	List(userID uint, unreadOnly bool, limit, page int) ([]models.Notification, int64, error)
+	MarkRead(userID, id uint) (*models.Notification, error)
+	MarkAllRead(userID uint) (int64, error)
+	Counts(userID uint) (*models.NotificationCounts, error)
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(r repository.NotificationRepository) NotificationService {
+	return &notificationService{repo: r}
+}
+
+func (s *notificationService) Notify(userID uint, kind models.NotificationType, title, body string, todoID *uint) (*models.Notification, error) {
+	// Titles embed user text; cut by characters so a multi-byte one is
+	// never split, which Postgres would reject.
+	if runes := []rune(title); len(runes) > 200 {
+		title = string(runes[:200])
+	}
+	n := &models.Notification{
+		UserID: userID,
+		Type:   kind,
+		Title:  title,
+		Body:   body,
+		TodoID: todoID,
+	}
+	if err := s.repo.Create(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (s *notificationService) List(userID uint, unreadOnly bool, limit, page int) ([]models.Notification, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return s.repo.FindAll(userID, unreadOnly, limit, (page-1)*limit)
+}
+
+func (s *notificationService) MarkRead(userID, id uint) (*models.Notification, error) {
+	return s.repo.MarkRead(userID, id)
+}
+
+func (s *notificationService) MarkAllRead(userID uint) (int64, error) {
+	return s.repo.MarkAllRead(userID)
+}
+
+func (s *notificationService) Counts(userID uint) (*models.NotificationCounts, error) {
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationCounts{Unread: unread}, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+type ProjectService interface {
+	List(actor Actor) ([]models.Project, error)
+	Get(id uint, actor Actor) (*models.Project, error)
+	Create(input *models.Project, actor Actor) (*models.Project, error)
+	Update(id uint, input *models.Project, actor Actor) (*models.Project, error)
+	Delete(id uint, actor Actor) error
+}
+
+type projectService struct {
+	repo      repository.ProjectRepository
+	access    AccessControl
+	validator *validator.Validate
+}
+
+func NewProjectService(r repository.ProjectRepository, access AccessControl) ProjectService {
+	return &projectService{repo: r, access: access, validator: validator.New()}
+}
+
+func (s *projectService) List(actor Actor) ([]models.Project, error) {
+	return s.repo.FindAccessible(actor.ID)
+}
+
+func (s *projectService) Get(id uint, actor Actor) (*models.Project, error) {
+	p, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Project(p, actor, models.PermissionViewer); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *projectService) Create(input *models.Project, actor Actor) (*models.Project, error) {
+	p := &models.Project{Name: input.Name, OwnerID: actor.ID}
+	if err := s.validator.Struct(p); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *projectService) Update(id uint, input *models.Project, actor Actor) (*models.Project, error) {
+	p, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Project(p, actor, models.PermissionEditor); err != nil {
+		return nil, err
+	}
+	if input.Name != "" {
+		p.Name = input.Name
+	}
+	if err := s.validator.Struct(p); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Delete removes a project; its todos are kept and simply lose the project.
+func (s *projectService) Delete(id uint, actor Actor) error {
+	p, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if !actor.IsAdmin() && p.OwnerID != actor.ID {
+		return ErrForbidden
+	}
+	return s.repo.Delete(id)
+}
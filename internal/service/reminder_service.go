@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+type ReminderService interface {
+	List(todoID uint, actor Actor) ([]models.Reminder, error)
+	Create(todoID uint, input *models.Reminder, actor Actor) (*models.Reminder, error)
+	Delete(todoID, id uint, actor Actor) error
+}
+
+type reminderService struct {
+	todos     repository.TodoRepository
+	repo      repository.ReminderRepository
+	access    AccessControl
+	validator *validator.Validate
+	// emailEnabled reports whether an SMTP server is configured; without
+	// one email reminders could never be delivered.
+	emailEnabled bool
+}
+
+func NewReminderService(todos repository.TodoRepository, r repository.ReminderRepository, access AccessControl, emailEnabled bool) ReminderService {
+	return &reminderService{todos: todos, repo: r, access: access, validator: validator.New(), emailEnabled: emailEnabled}
+}
+
+func (s *reminderService) List(todoID uint, actor Actor) ([]models.Reminder, error) {
+	if _, err := s.todo(todoID, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTodo(todoID)
+}
+
+// Create adds a reminder that fires either at an absolute time (remind_at)
+// or a number of minutes before the todo's due date (offset_minutes). Anyone
+// who can view the todo may set reminders for themselves. Email reminders
+// always go to the reminder owner's own address; only webhooks take a target.
+func (s *reminderService) Create(todoID uint, input *models.Reminder, actor Actor) (*models.Reminder, error) {
+	todo, err := s.todo(todoID, actor)
+	if err != nil {
+		return nil, err
+	}
+	rem := &models.Reminder{
+		TodoID:        todoID,
+		UserID:        actor.ID,
+		RemindAt:      input.RemindAt,
+		OffsetMinutes: input.OffsetMinutes,
+		Channel:       input.Channel,
+		Target:        input.Target,
+	}
+	if rem.Channel == "" {
+		rem.Channel = models.ReminderInApp
+	}
+	if err := s.validator.Struct(rem); err != nil {
+		return nil, err
+	}
+	switch {
+	case rem.RemindAt != nil && rem.OffsetMinutes != nil:
+		return nil, errors.New("set either remind_at or offset_minutes, not both")
+	case rem.RemindAt != nil:
+		if rem.RemindAt.Before(time.Now()) {
+			return nil, errors.New("remind_at must be in the future")
+		}
+		rem.FireAt = rem.RemindAt
+	case rem.OffsetMinutes != nil:
+		if todo.DueDate == nil {
+			return nil, errors.New("offset reminders require the todo to have a due_date")
+		}
+		at := todo.DueDate.Add(-time.Duration(*rem.OffsetMinutes) * time.Minute)
+		rem.FireAt = &at
+	default:
+		return nil, errors.New("remind_at or offset_minutes is required")
+	}
+	switch rem.Channel {
+	case models.ReminderWebhook:
+		u, err := url.Parse(rem.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errors.New("webhook reminders require an http(s) target URL")
+		}
+	case models.ReminderEmail:
+		if !s.emailEnabled {
+			return nil, errors.New("email reminders are not available: no SMTP server is configured")
+		}
+		rem.Target = ""
+	default:
+		rem.Target = ""
+	}
+	if err := s.repo.Create(rem); err != nil {
+		return nil, err
+	}
+	return rem, nil
+}
+
+// Delete removes a reminder. Users may delete their own reminders; other
+// people's reminders require edit access to the todo.
+func (s *reminderService) Delete(todoID, id uint, actor Actor) error {
+	todo, err := s.todo(todoID, actor)
+	if err != nil {
+		return err
+	}
+	rem, err := s.repo.FindByID(todoID, id)
+	if err != nil {
+		return err
+	}
+	if rem.UserID != actor.ID {
+		if err := s.access.Todo(todo, actor, models.PermissionEditor); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(todoID, id)
+}
+
+func (s *reminderService) todo(todoID uint, actor Actor) (*models.Todo, error) {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Todo(todo, actor, models.PermissionViewer); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
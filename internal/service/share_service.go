@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+)
+
+// ShareInput identifies the collaborator by id or email.
+type ShareInput struct {
+	UserID     uint              `json:"user_id"`
+	Email      string            `json:"email"`
+	Permission models.Permission `json:"permission"`
+}
+
+type ShareService interface {
+	ListTodoShares(todoID uint, actor Actor) ([]models.Share, error)
+	ShareTodo(todoID uint, input ShareInput, actor Actor) (*models.Share, error)
+	RevokeTodoShare(todoID, userID uint, actor Actor) error
+	ListProjectShares(projectID uint, actor Actor) ([]models.Share, error)
+	ShareProject(projectID uint, input ShareInput, actor Actor) (*models.Share, error)
+	RevokeProjectShare(projectID, userID uint, actor Actor) error
+}
+
+type shareService struct {
+	repo          repository.ShareRepository
+	todos         repository.TodoRepository
+	projects      repository.ProjectRepository
+	users         repository.UserRepository
+	notifications NotificationService
+	urls          *storage.URLSigner
+}
+
+func NewShareService(
+	r repository.ShareRepository,
+	todos repository.TodoRepository,
+	projects repository.ProjectRepository,
+	users repository.UserRepository,
+	notifications NotificationService,
+	urls *storage.URLSigner,
+) ShareService {
+	return &shareService{repo: r, todos: todos, projects: projects, users: users, notifications: notifications, urls: urls}
+}
+
+func (s *shareService) ListTodoShares(todoID uint, actor Actor) ([]models.Share, error) {
+	if _, err := s.ownedTodo(todoID, actor); err != nil {
+		return nil, err
+	}
+	return s.withUsers(s.repo.FindByTodo(todoID))
+}
+
+func (s *shareService) ShareTodo(todoID uint, input ShareInput, actor Actor) (*models.Share, error) {
+	todo, err := s.ownedTodo(todoID, actor)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.collaborator(input, todo.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	share := &models.Share{TodoID: &todo.ID, UserID: user.ID, Permission: input.Permission, CreatedBy: actor.ID}
+	if err := s.save(share); err != nil {
+		return nil, err
+	}
+	s.notify(user.ID, fmt.Sprintf("A todo was shared with you: %s", todo.Title), share, &todo.ID)
+	share.User = user
+	signUser(s.urls, share.User)
+	return share, nil
+}
+
+// RevokeTodoShare removes a collaborator. Owners can revoke anyone;
+// collaborators can remove themselves.
+func (s *shareService) RevokeTodoShare(todoID, userID uint, actor Actor) error {
+	if actor.ID != userID {
+		if _, err := s.ownedTodo(todoID, actor); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteTodoShare(todoID, userID)
+}
+
+func (s *shareService) ListProjectShares(projectID uint, actor Actor) ([]models.Share, error) {
+	if _, err := s.ownedProject(projectID, actor); err != nil {
+		return nil, err
+	}
+	return s.withUsers(s.repo.FindByProject(projectID))
+}
+
+func (s *shareService) ShareProject(projectID uint, input ShareInput, actor Actor) (*models.Share, error) {
+	p, err := s.ownedProject(projectID, actor)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.collaborator(input, p.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	share := &models.Share{ProjectID: &p.ID, UserID: user.ID, Permission: input.Permission, CreatedBy: actor.ID}
+	if err := s.save(share); err != nil {
+		return nil, err
+	}
+	s.notify(user.ID, fmt.Sprintf("A project was shared with you: %s", p.Name), share, nil)
+	share.User = user
+	signUser(s.urls, share.User)
+	return share, nil
+}
+
+// withUsers signs the avatar URLs of the collaborators in shares.
+func (s *shareService) withUsers(shares []models.Share, err error) ([]models.Share, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		signUser(s.urls, shares[i].User)
+	}
+	return shares, nil
+}
+
+func (s *shareService) RevokeProjectShare(projectID, userID uint, actor Actor) error {
+	if actor.ID != userID {
+		if _, err := s.ownedProject(projectID, actor); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteProjectShare(projectID, userID)
+}
+
+// ownedTodo loads a todo that only its owner (or an admin) may share.
+func (s *shareService) ownedTodo(todoID uint, actor Actor) (*models.Todo, error) {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin() && todo.OwnerID != actor.ID {
+		return nil, ErrForbidden
+	}
+	return todo, nil
+}
+
+func (s *shareService) ownedProject(projectID uint, actor Actor) (*models.Project, error) {
+	p, err := s.projects.FindByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin() && p.OwnerID != actor.ID {
+		return nil, ErrForbidden
+	}
+	return p, nil
+}
+
+func (s *shareService) collaborator(input ShareInput, ownerID uint) (*models.User, error) {
+	var (
+		user *models.User
+		err  error
+	)
+	switch {
+	case input.UserID != 0:
+		user, err = s.users.FindByID(input.UserID)
+	case input.Email != "":
+		user, err = s.users.FindByEmail(input.Email)
+	default:
+		return nil, errors.New("user_id or email is required")
+	}
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.ID == ownerID {
+		return nil, errors.New("cannot share with the owner")
+	}
+	return user, nil
+}
+
+func (s *shareService) save(share *models.Share) error {
+	if share.Permission == "" {
+		share.Permission = models.PermissionViewer
+	}
+	if share.Permission != models.PermissionViewer && share.Permission != models.PermissionEditor {
+		return errors.New("permission must be viewer or editor")
+	}
+	return s.repo.Upsert(share)
+}
+
+// notify tells the collaborator about the share. Failures are not fatal:
+// the share itself has already been stored.
+func (s *shareService) notify(userID uint, title string, share *models.Share, todoID *uint) {
+	body := fmt.Sprintf("Permission: %s", share.Permission)
+	_, _ = s.notifications.Notify(userID, models.NotificationShare, title, body, todoID)
+}
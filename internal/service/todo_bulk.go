@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// MaxBulkTodos caps how many todos one bulk request may touch.
+const MaxBulkTodos = 500
+
+type BulkOp string
+
+const (
+	BulkComplete    BulkOp = "complete"
+	BulkReopen      BulkOp = "reopen"
+	BulkDelete      BulkOp = "delete"
+	BulkSetPriority BulkOp = "set_priority"
+	BulkMove        BulkOp = "move"
+	BulkTag         BulkOp = "tag"
+	BulkUntag       BulkOp = "untag"
+)
+
+// BulkRequest selects todos either by IDs or by Filter and applies Op to
+// each of them. Priority, ProjectID and Tags are the operation's argument.
+type BulkRequest struct {
+	IDs       []uint                 `json:"ids"`
+	Filter    *repository.TodoFilter `json:"-"`
+	Op        BulkOp                 `json:"op"`
+	Priority  models.Priority        `json:"priority"`
+	ProjectID *uint                  `json:"project_id"`
+	Tags      []string               `json:"tags"`
+	// Atomic rolls back every change if any single todo fails.
+	Atomic bool `json:"atomic"`
+}
+
+type BulkItemResult struct {
+	ID    uint   `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type BulkResult struct {
+	Op         BulkOp           `json:"op"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	RolledBack bool             `json:"rolled_back"`
+	Results    []BulkItemResult `json:"results"`
+}
+
+// ErrBulkRolledBack is returned with the result of an atomic bulk request
+// in which at least one todo failed.
+var ErrBulkRolledBack = errors.New("bulk operation rolled back: some todos failed")
+
+// Bulk applies one operation to many todos in a single transaction. Each
+// todo runs in its own savepoint, so a todo the actor may not change is
+// reported and skipped without affecting the others, unless the request is
+// atomic.
+func (s *todoService) Bulk(req BulkRequest, actor Actor) (*BulkResult, error) {
+	if err := validateBulk(&req); err != nil {
+		return nil, err
+	}
+	ids, err := s.bulkTargets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{Op: req.Op, Results: make([]BulkItemResult, 0, len(ids))}
+	var orphanedBlobs []string
+	err = s.tx.Do(func(r repository.Repos) error {
+		var tags []models.Tag
+		if req.Op == BulkTag || req.Op == BulkUntag {
+			var err error
+			if tags, err = r.Tags.FindOrCreate(req.Tags); err != nil {
+				return err
+			}
+		}
+		var pending []string
+		for _, id := range ids {
+			var keys []string
+			err := r.Tx.Do(func(item repository.Repos) error {
+				var err error
+				keys, err = s.withRepos(item).bulkOne(item, id, req, tags, actor)
+				return err
+			})
+			res := BulkItemResult{ID: id, OK: err == nil}
+			if err != nil {
+				res.Error = err.Error()
+				result.Failed++
+			} else {
+				result.Succeeded++
+				pending = append(pending, keys...)
+			}
+			result.Results = append(result.Results, res)
+		}
+		if req.Atomic && result.Failed > 0 {
+			return ErrBulkRolledBack
+		}
+		orphanedBlobs = pending
+		return nil
+	})
+	if errors.Is(err, ErrBulkRolledBack) {
+		result.RolledBack = true
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	removeBlobs(s.blobs, orphanedBlobs...)
+	return result, nil
+}
+
+// bulkOne applies the operation to a single todo using repositories bound
+// to that todo's savepoint. It returns blob keys to delete after commit.
+func (s *todoService) bulkOne(r repository.Repos, id uint, req BulkRequest, tags []models.Tag, actor Actor) ([]string, error) {
+	switch req.Op {
+	case BulkComplete, BulkReopen:
+		_, err := s.ToggleComplete(id, req.Op == BulkComplete, nil, actor)
+		return nil, err
+	case BulkDelete:
+		return s.delete(id, actor)
+	case BulkSetPriority, BulkMove:
+		existing, err := s.editable(id, nil, actor)
+		if err != nil {
+			return nil, err
+		}
+		input := patchableOf(existing)
+		if req.Op == BulkSetPriority {
+			input.Priority = req.Priority
+		} else {
+			input.ProjectID = req.ProjectID
+		}
+		_, err = s.save(existing, input.todo(), nil, actor)
+		return nil, err
+	case BulkTag, BulkUntag:
+		existing, err := s.editable(id, nil, actor)
+		if err != nil {
+			return nil, err
+		}
+		change := models.TodoActivity{TodoID: id, ActorID: actor.ID, Field: "tags"}
+		if req.Op == BulkTag {
+			err = r.Tags.Attach(id, tags)
+			change.NewValue = tagNames(tags)
+		} else {
+			err = r.Tags.Detach(id, tags)
+			change.OldValue = tagNames(tags)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Tags are not a column; saving the todo still bumps its version
+		// and updated_at so caches and syncing clients see the change.
+		before := *existing
+		if err := s.repo.Update(existing); err != nil {
+			return nil, err
+		}
+		after, err := s.repo.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.activity.Record([]models.TodoActivity{change}); err != nil {
+			return nil, err
+		}
+		return nil, s.publishChange(&before, after)
+	}
+	return nil, fmt.Errorf("unknown op %q", req.Op)
+}
+
+// bulkTargets resolves the request to a deduplicated list of todo IDs.
+func (s *todoService) bulkTargets(req BulkRequest) ([]uint, error) {
+	var ids []uint
+	if req.Filter != nil {
+		page, err := s.repo.FindAll(*req.Filter, repository.PageRequest{Limit: MaxBulkTodos + 1})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range page.Todos {
+			ids = append(ids, t.ID)
+		}
+	} else {
+		seen := make(map[uint]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			if id != 0 && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no todos selected")
+	}
+	if len(ids) > MaxBulkTodos {
+		return nil, fmt.Errorf("at most %d todos per bulk request", MaxBulkTodos)
+	}
+	return ids, nil
+}
+
+func validateBulk(req *BulkRequest) error {
+	if (len(req.IDs) > 0) == (req.Filter != nil) {
+		return errors.New("set either ids or filter")
+	}
+	switch req.Op {
+	case BulkComplete, BulkReopen, BulkDelete, BulkMove:
+	case BulkSetPriority:
+		switch req.Priority {
+		case models.PriorityLow, models.PriorityMedium, models.PriorityHigh:
+		default:
+			return errors.New("priority must be low, medium or high")
+		}
+	case BulkTag, BulkUntag:
+		names, err := normalizeTags(req.Tags)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return errors.New("tags are required")
+		}
+		req.Tags = names
+	default:
+		return fmt.Errorf("unknown op %q (complete|reopen|delete|set_priority|move|tag|untag)", req.Op)
+	}
+	return nil
+}
+
+// normalizeTags lowercases, trims, deduplicates and sorts tag names.
+func normalizeTags(tags []string) ([]string, error) {
+	names := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || len(t) > 50 {
+			return nil, errors.New("tags must be 1-50 characters")
+		}
+		if !seen[t] {
+			seen[t] = true
+			names = append(names, t)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func tagNames(tags []models.Tag) string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+type TodoItemService interface {
+	List(todoID uint, actor Actor) ([]models.TodoItem, error)
+	Create(todoID uint, input *models.TodoItem, actor Actor) (*models.TodoItem, error)
+	Update(todoID, id uint, input *models.TodoItem, actor Actor) (*models.TodoItem, error)
+	Toggle(todoID, id uint, completed bool, actor Actor) (*models.TodoItem, *models.Todo, error)
+	Delete(todoID, id uint, actor Actor) error
+	Reorder(todoID uint, ids []uint, actor Actor) ([]models.TodoItem, error)
+}
+
+// todoItemService reads checklists directly; writes that can change a
+// todo's progress go through TodoService.UpdateChecklist so that the todo
+// is completed or reopened in the same transaction.
+type todoItemService struct {
+	todos     repository.TodoRepository
+	todoSvc   TodoService
+	repo      repository.TodoItemRepository
+	access    AccessControl
+	validator *validator.Validate
+}
+
+func NewTodoItemService(todos repository.TodoRepository, todoSvc TodoService, r repository.TodoItemRepository, access AccessControl) TodoItemService {
+	return &todoItemService{todos: todos, todoSvc: todoSvc, repo: r, access: access, validator: validator.New()}
+}
+
+func (s *todoItemService) List(todoID uint, actor Actor) ([]models.TodoItem, error) {
+	if _, err := s.todo(todoID, actor, models.PermissionViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTodo(todoID)
+}
+
+func (s *todoItemService) Create(todoID uint, input *models.TodoItem, actor Actor) (*models.TodoItem, error) {
+	item := &models.TodoItem{
+		TodoID:    todoID,
+		Title:     input.Title,
+		Completed: input.Completed,
+	}
+	if err := s.validator.Struct(item); err != nil {
+		return nil, err
+	}
+	_, err := s.todoSvc.UpdateChecklist(todoID, actor, func(items repository.TodoItemRepository) error {
+		pos, err := items.NextPosition(todoID)
+		if err != nil {
+			return err
+		}
+		item.Position = pos
+		return items.Create(item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *todoItemService) Update(todoID, id uint, input *models.TodoItem, actor Actor) (*models.TodoItem, error) {
+	if _, err := s.todo(todoID, actor, models.PermissionEditor); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.FindByID(todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Title != "" {
+		item.Title = input.Title
+	}
+	if err := s.validator.Struct(item); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Toggle sets the completion state of one checklist item and returns it
+// together with the parent todo, which may have been completed or reopened.
+func (s *todoItemService) Toggle(todoID, id uint, completed bool, actor Actor) (*models.TodoItem, *models.Todo, error) {
+	var item *models.TodoItem
+	todo, err := s.todoSvc.UpdateChecklist(todoID, actor, func(items repository.TodoItemRepository) (err error) {
+		if item, err = items.FindByID(todoID, id); err != nil {
+			return err
+		}
+		item.Completed = completed
+		return items.Update(item)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, todo, nil
+}
+
+func (s *todoItemService) Delete(todoID, id uint, actor Actor) error {
+	_, err := s.todoSvc.UpdateChecklist(todoID, actor, func(items repository.TodoItemRepository) error {
+		return items.Delete(todoID, id)
+	})
+	return err
+}
+
+func (s *todoItemService) Reorder(todoID uint, ids []uint, actor Actor) ([]models.TodoItem, error) {
+	if _, err := s.todo(todoID, actor, models.PermissionEditor); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Reorder(todoID, ids); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTodo(todoID)
+}
+
+// todo loads the parent todo and checks the actor's access to it.
+func (s *todoItemService) todo(todoID uint, actor Actor, need models.Permission) (*models.Todo, error) {
+	todo, err := s.todos.FindByID(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Todo(todo, actor, need); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// PatchKind selects how a PATCH body is interpreted.
+type PatchKind string
+
+const (
+	MergePatch PatchKind = "merge" // RFC 7396, application/merge-patch+json
+	JSONPatch  PatchKind = "json"  // RFC 6902, application/json-patch+json
+)
+
+// todoPatchable is the document patches are applied to: the fields a
+// client may change, with null meaning "no value".
+type todoPatchable struct {
+	Title        string          `json:"title" validate:"required,min=3,max=200"`
+	Description  string          `json:"description" validate:"max=2000"`
+	Completed    bool            `json:"completed"`
+	DueDate      *time.Time      `json:"due_date"`
+	Priority     models.Priority `json:"priority" validate:"required,oneof=low medium high"`
+	ProjectID    *uint           `json:"project_id"`
+	AutoComplete bool            `json:"auto_complete"`
+	RRule        string          `json:"rrule"`
+}
+
+func patchableOf(t *models.Todo) todoPatchable {
+	return todoPatchable{
+		Title:        t.Title,
+		Description:  t.Description,
+		Completed:    t.Completed,
+		DueDate:      t.DueDate,
+		Priority:     t.Priority,
+		ProjectID:    t.ProjectID,
+		AutoComplete: t.AutoComplete,
+		RRule:        t.RRule,
+	}
+}
+
+func (p *todoPatchable) todo() *models.Todo {
+	return &models.Todo{
+		Title:        p.Title,
+		Description:  p.Description,
+		Completed:    p.Completed,
+		DueDate:      p.DueDate,
+		Priority:     p.Priority,
+		ProjectID:    p.ProjectID,
+		AutoComplete: p.AutoComplete,
+		RRule:        p.RRule,
+	}
+}
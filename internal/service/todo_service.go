@@ -1,47 +1,188 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/events"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/jsonpatch"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/recurrence"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
 )
 
 type TodoService interface {
-	List(limit, page int, search string, completed *bool, priority *models.Priority, sort string, ownerID *uint) ([]models.Todo, int64, error)
-	Get(id uint) (*models.Todo, error)
-	Create(input *models.Todo) (*models.Todo, error)
-	Update(id uint, input *models.Todo) (*models.Todo, error)
-	Delete(id uint) error
-	ToggleComplete(id uint, completed bool) (*models.Todo, error)
+	List(filter repository.TodoFilter, page repository.PageRequest) (*repository.TodoPage, error)
+	Get(id uint, actor Actor) (*models.Todo, error)
+	Create(input *models.Todo, actor Actor) (*models.Todo, error)
+	Update(id uint, input *models.Todo, ifMatch *uint, actor Actor) (*models.Todo, error)
+	Patch(id uint, kind PatchKind, patch []byte, ifMatch *uint, actor Actor) (*models.Todo, error)
+	Delete(id uint, actor Actor) error
+	ToggleComplete(id uint, completed bool, ifMatch *uint, actor Actor) (*models.Todo, error)
+	UpdateChecklist(id uint, actor Actor, write func(items repository.TodoItemRepository) error) (*models.Todo, error)
+	Bulk(req BulkRequest, actor Actor) (*BulkResult, error)
+	Export(filter repository.TodoFilter, fn func(todos []models.Todo) error) error
+	Import(records []todoio.Record, dryRun bool, actor Actor) (*ImportResult, error)
+	InTx(fn func(todos TodoService, r repository.Repos) error) error
+	Now(actor Actor) time.Time
 }
 
 type todoService struct {
-	repo      repository.TodoRepository
-	validator *validator.Validate
+	repo        repository.TodoRepository
+	items       repository.TodoItemRepository
+	reminders   repository.ReminderRepository
+	activity    repository.ActivityRepository
+	users       repository.UserRepository
+	attachments repository.AttachmentRepository
+	outbox      repository.OutboxRepository
+	blobs       storage.BlobStore
+	access      AccessControl
+	tx          repository.TxManager
+	validator   *validator.Validate
 }
 
-func NewTodoService(r repository.TodoRepository) TodoService {
-	return &todoService{repo: r, validator: validator.New()}
+func NewTodoService(
+	r repository.TodoRepository,
+	items repository.TodoItemRepository,
+	reminders repository.ReminderRepository,
+	activity repository.ActivityRepository,
+	users repository.UserRepository,
+	attachments repository.AttachmentRepository,
+	outbox repository.OutboxRepository,
+	blobs storage.BlobStore,
+	access AccessControl,
+	tx repository.TxManager,
+) TodoService {
+	return &todoService{
+		repo:        r,
+		items:       items,
+		reminders:   reminders,
+		activity:    activity,
+		users:       users,
+		attachments: attachments,
+		outbox:      outbox,
+		blobs:       blobs,
+		access:      access,
+		tx:          tx,
+		validator:   validator.New(),
+	}
+}
+
+// withRepos returns a copy of s whose writes go through r, typically
+// repositories bound to a transaction.
+func (s *todoService) withRepos(r repository.Repos) *todoService {
+	c := *s
+	c.repo = r.Todos
+	c.items = r.Items
+	c.reminders = r.Reminders
+	c.activity = r.Activity
+	c.attachments = r.Attachments
+	c.outbox = r.Outbox
+	c.access = NewAccessControl(r.Shares, r.Projects)
+	c.tx = r.Tx
+	return &c
+}
+
+// inTx runs fn against a copy of s bound to a transaction, so a write and
+// the domain events it publishes commit together. Called on a copy that is
+// already bound, the transaction nests as a savepoint.
+func (s *todoService) inTx(fn func(c *todoService) error) error {
+	return s.tx.Do(func(r repository.Repos) error {
+		return fn(s.withRepos(r))
+	})
+}
+
+// Now returns the current time in the actor's timezone. Relative times in
+// filter expressions (today, now) are resolved against it, so an expression
+// means the same on every endpoint that takes one.
+func (s *todoService) Now(actor Actor) time.Time {
+	return time.Now().In(userLocation(s.users, actor.ID))
+}
+
+// InTx runs fn in one transaction with a TodoService and repositories
+// bound to it, for callers that combine several writes into one unit.
+func (s *todoService) InTx(fn func(todos TodoService, r repository.Repos) error) error {
+	return s.tx.Do(func(r repository.Repos) error {
+		return fn(s.withRepos(r), r)
+	})
 }
 
-func (s *todoService) List(limit, page int, search string, completed *bool, priority *models.Priority, sort string, ownerID *uint) ([]models.Todo, int64, error) {
+const (
+	// DefaultPageSize is the limit of a todo listing that asks for none.
+	DefaultPageSize = 10
+	// MaxPageSize caps the limit of a todo listing.
+	MaxPageSize = 100
+)
+
+// PageSize clamps a requested todo listing limit to 1..MaxPageSize,
+// falling back to DefaultPageSize for a missing or invalid one.
+func PageSize(limit int) int {
 	if limit <= 0 {
-		limit = 10
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
+
+func (s *todoService) List(filter repository.TodoFilter, page repository.PageRequest) (*repository.TodoPage, error) {
+	page.Limit = PageSize(page.Limit)
+	if page.After != nil && page.Before != nil {
+		return nil, errors.New("use either after or before, not both")
+	}
+	result, err := s.repo.FindAll(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachProgress(result.Todos); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *todoService) Get(id uint, actor Actor) (*models.Todo, error) {
+	todo, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.Todo(todo, actor, models.PermissionViewer); err != nil {
+		return nil, err
 	}
-	if page <= 0 {
-		page = 1
+	items, err := s.items.FindByTodo(id)
+	if err != nil {
+		return nil, err
 	}
-	offset := (page - 1) * limit
-	return s.repo.FindAll(limit, offset, search, completed, priority, sort, ownerID)
+	todo.Items = items
+	todo.Progress = progressOf(items)
+	return todo, nil
 }
 
-func (s *todoService) Get(id uint) (*models.Todo, error) {
-	return s.repo.FindByID(id)
+func (s *todoService) Create(input *models.Todo, actor Actor) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.inTx(func(c *todoService) (err error) {
+		todo, err = c.create(input, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
 }
 
-func (s *todoService) Create(input *models.Todo) (*models.Todo, error) {
+func (s *todoService) create(input *models.Todo, actor Actor) (*models.Todo, error) {
+	input.OwnerID = actor.ID
+	if input.ProjectID != nil {
+		if err := s.access.ProjectByID(*input.ProjectID, actor, models.PermissionEditor); err != nil {
+			return nil, err
+		}
+	}
 	if input.Priority == "" {
 		input.Priority = models.PriorityMedium
 	}
@@ -52,20 +193,143 @@ func (s *todoService) Create(input *models.Todo) (*models.Todo, error) {
 		d := time.Now()
 		input.DueDate = &d
 	}
+	if err := normalizeRRule(input); err != nil {
+		return nil, err
+	}
+	if input.ExternalID != nil && *input.ExternalID == "" {
+		input.ExternalID = nil
+	}
+	input.SeriesID = nil
+	input.Occurrence = 1
+	input.Version = 1
+	input.Reminders = nil
+	input.Shares = nil
+	input.Comments = nil
+	input.Activities = nil
+	input.Attachments = nil
+	input.Tags = nil
+	for i := range input.Items {
+		input.Items[i].ID = 0
+		input.Items[i].Position = i
+		if err := s.validator.Struct(&input.Items[i]); err != nil {
+			return nil, err
+		}
+	}
 	if err := s.validator.Struct(input); err != nil {
 		return nil, err
 	}
 	if err := s.repo.Create(input); err != nil {
 		return nil, err
 	}
+	if err := s.activity.Record([]models.TodoActivity{{TodoID: input.ID, ActorID: actor.ID, Field: "created", NewValue: input.Title}}); err != nil {
+		return nil, err
+	}
+	if err := s.publish(events.TodoCreated{Todo: input}); err != nil {
+		return nil, err
+	}
+	input.Progress = progressOf(input.Items)
 	return input, nil
 }
 
-func (s *todoService) Update(id uint, input *models.Todo) (*models.Todo, error) {
+// Update replaces the editable fields of a todo. When ifMatch is set the
+// update only succeeds if the todo is still at that version.
+func (s *todoService) Update(id uint, input *models.Todo, ifMatch *uint, actor Actor) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.inTx(func(c *todoService) error {
+		existing, err := c.editable(id, ifMatch, actor)
+		if err != nil {
+			return err
+		}
+		todo, err = c.save(existing, input, ifMatch, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// Patch applies a JSON merge patch or JSON patch to the editable fields of
+// a todo. Fields the patch leaves alone keep their values; fields it
+// removes or sets to null are cleared.
+func (s *todoService) Patch(id uint, kind PatchKind, patch []byte, ifMatch *uint, actor Actor) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.inTx(func(c *todoService) (err error) {
+		todo, err = c.patch(id, kind, patch, ifMatch, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+func (s *todoService) patch(id uint, kind PatchKind, patch []byte, ifMatch *uint, actor Actor) (*models.Todo, error) {
+	existing, err := s.editable(id, ifMatch, actor)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := json.Marshal(patchableOf(existing))
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case MergePatch:
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	case JSONPatch:
+		doc, err = jsonpatch.Apply(doc, patch)
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patched todoPatchable
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patched); err != nil {
+		return nil, fmt.Errorf("invalid patch result: %w", err)
+	}
+	if err := s.validator.Struct(&patched); err != nil {
+		return nil, err
+	}
+	return s.save(existing, patched.todo(), ifMatch, actor)
+}
+
+// editable loads a todo the actor may edit and checks the If-Match version.
+func (s *todoService) editable(id uint, ifMatch *uint, actor Actor) (*models.Todo, error) {
 	existing, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.access.Todo(existing, actor, models.PermissionEditor); err != nil {
+		return nil, err
+	}
+	if ifMatch != nil && *ifMatch != existing.Version {
+		return nil, ErrPreconditionFailed
+	}
+	return existing, nil
+}
+
+// save copies the editable fields of input onto existing and persists it.
+// A change of the completion state goes through toggleComplete afterwards,
+// so recurring todos spawn their next occurrence and checklists follow, the
+// same as on the toggle endpoint. Callers run it inside a transaction.
+func (s *todoService) save(existing, input *models.Todo, ifMatch *uint, actor Actor) (*models.Todo, error) {
+	before := *existing
+	if !sameID(existing.ProjectID, input.ProjectID) {
+		// Moving between projects is reserved to the owner, who must be
+		// able to edit the target project.
+		if !actor.IsAdmin() && existing.OwnerID != actor.ID {
+			return nil, ErrForbidden
+		}
+		if input.ProjectID != nil {
+			if err := s.access.ProjectByID(*input.ProjectID, actor, models.PermissionEditor); err != nil {
+				return nil, err
+			}
+		}
+		existing.ProjectID = input.ProjectID
+	}
 	if input.Title != "" {
 		existing.Title = input.Title
 	}
@@ -73,22 +337,342 @@ func (s *todoService) Update(id uint, input *models.Todo) (*models.Todo, error)
 	if input.Priority != "" {
 		existing.Priority = input.Priority
 	}
+	dueChanged := !sameTime(existing.DueDate, input.DueDate)
 	existing.DueDate = input.DueDate
-	existing.Completed = input.Completed
+	completedChanged := input.Completed != existing.Completed
+	existing.AutoComplete = input.AutoComplete
+	existing.RRule = input.RRule
+	if err := normalizeRRule(existing); err != nil {
+		return nil, err
+	}
 
 	if err := s.validator.Struct(existing); err != nil {
 		return nil, err
 	}
 	if err := s.repo.Update(existing); err != nil {
+		if ifMatch != nil && errors.Is(err, repository.ErrVersionConflict) {
+			return nil, ErrPreconditionFailed
+		}
 		return nil, err
 	}
+	if dueChanged {
+		if err := s.reminders.Reschedule(existing.ID, existing.DueDate); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.activity.Record(diffTodo(&before, existing, actor.ID)); err != nil {
+		return nil, err
+	}
+	if err := s.publishChange(&before, existing); err != nil {
+		return nil, err
+	}
+	if completedChanged {
+		return s.toggleComplete(existing.ID, input.Completed, nil, actor)
+	}
 	return existing, nil
 }
 
-func (s *todoService) Delete(id uint) error {
-	return s.repo.Delete(id)
+// Delete removes a todo. Deleting is reserved to admins, whether it comes
+// through DELETE /todos/:id, bulk delete or CalDAV. Attachment rows go with
+// the todo through the foreign key; their files are removed from the blob
+// store afterwards.
+func (s *todoService) Delete(id uint, actor Actor) error {
+	var keys []string
+	err := s.inTx(func(c *todoService) (err error) {
+		keys, err = c.delete(id, actor)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	removeBlobs(s.blobs, keys...)
+	return nil
 }
 
-func (s *todoService) ToggleComplete(id uint, completed bool) (*models.Todo, error) {
-	return s.repo.ToggleComplete(id, completed)
+// delete removes a todo and returns the blob keys of its attachments.
+func (s *todoService) delete(id uint, actor Actor) ([]string, error) {
+	todo, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin() {
+		return nil, ErrForbidden
+	}
+	keys, err := s.attachments.StorageKeys(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return nil, err
+	}
+	return keys, s.publish(events.TodoDeletedOf(todo))
+}
+
+// ToggleComplete sets the completion state of a todo. Completing a todo that
+// auto-completes from its checklist also checks off its remaining items.
+// Reopening it leaves the items as they are: the parent may then be open
+// with every item done, until the next change to the checklist, where the
+// checklist wins and the parent follows it again (see syncChecklist).
+//
+// Completing an occurrence of a recurring todo creates the next occurrence
+// with its due date shifted by the rule, evaluated in the owner's timezone.
+// When ifMatch is set the toggle only succeeds if the todo is still at that
+// version.
+func (s *todoService) ToggleComplete(id uint, completed bool, ifMatch *uint, actor Actor) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.inTx(func(c *todoService) (err error) {
+		todo, err = c.toggleComplete(id, completed, ifMatch, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+func (s *todoService) toggleComplete(id uint, completed bool, ifMatch *uint, actor Actor) (*models.Todo, error) {
+	before, err := s.editable(id, ifMatch, actor)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.repo.ToggleComplete(id, completed, ifMatch)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, ErrPreconditionFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.activity.Record(diffTodo(before, todo, actor.ID)); err != nil {
+		return nil, err
+	}
+	if err := s.publishChange(before, todo); err != nil {
+		return nil, err
+	}
+	if completed && todo.AutoComplete {
+		if err := s.items.SetAllCompleted(id, true); err != nil {
+			return nil, err
+		}
+	}
+	if completed && !before.Completed && todo.RRule != "" {
+		next, err := s.spawnNext(todo)
+		if err != nil {
+			return nil, err
+		}
+		todo.Next = next
+	}
+	progress, err := s.items.Progress([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := progress[id]; ok {
+		todo.Progress = &p
+	}
+	return todo, nil
+}
+
+// UpdateChecklist applies write to the checklist items of a todo the actor
+// may edit and keeps an auto-completing todo in line with them, in one
+// transaction: the todo is completed once all items are done and reopened
+// when one is unchecked, the same way ToggleComplete does it. It returns
+// the todo with its progress.
+func (s *todoService) UpdateChecklist(id uint, actor Actor, write func(items repository.TodoItemRepository) error) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.inTx(func(c *todoService) error {
+		t, err := c.repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := c.access.Todo(t, actor, models.PermissionEditor); err != nil {
+			return err
+		}
+		if err := write(c.items); err != nil {
+			return err
+		}
+		todo, err = c.syncChecklist(t, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// syncChecklist makes an auto-completing todo follow its checklist after
+// the items changed: done when all items are, open otherwise.
+func (s *todoService) syncChecklist(todo *models.Todo, actor Actor) (*models.Todo, error) {
+	progress, err := s.items.Progress([]uint{todo.ID})
+	if err != nil {
+		return nil, err
+	}
+	p := progress[todo.ID]
+	if p.Total > 0 {
+		todo.Progress = &p
+	}
+	if !todo.AutoComplete || p.Total == 0 {
+		return todo, nil
+	}
+	done := p.Done == p.Total
+	if done == todo.Completed {
+		return todo, nil
+	}
+	return s.toggleComplete(todo.ID, done, nil, actor)
+}
+
+func (s *todoService) attachProgress(todos []models.Todo) error {
+	ids := make([]uint, len(todos))
+	for i := range todos {
+		ids[i] = todos[i].ID
+	}
+	progress, err := s.items.Progress(ids)
+	if err != nil {
+		return err
+	}
+	for i := range todos {
+		if p, ok := progress[todos[i].ID]; ok {
+			todos[i].Progress = &p
+		}
+	}
+	return nil
+}
+
+func progressOf(items []models.TodoItem) *models.Progress {
+	if len(items) == 0 {
+		return nil
+	}
+	p := &models.Progress{Total: int64(len(items))}
+	for _, it := range items {
+		if it.Completed {
+			p.Done++
+		}
+	}
+	return p
+}
+
+// spawnNext creates the occurrence following todo, unless the series has
+// ended or the next occurrence already exists (e.g. after re-completing).
+func (s *todoService) spawnNext(todo *models.Todo) (*models.Todo, error) {
+	if todo.DueDate == nil {
+		return nil, nil
+	}
+	rule, err := recurrence.Parse(todo.RRule)
+	if err != nil {
+		return nil, err
+	}
+	due, ok := rule.Next(*todo.DueDate, todo.Occurrence, s.ownerLocation(todo.OwnerID))
+	if !ok {
+		return nil, nil
+	}
+	seriesID := todo.ID
+	if todo.SeriesID != nil {
+		seriesID = *todo.SeriesID
+	}
+	if existing, err := s.repo.FindOccurrence(seriesID, todo.Occurrence+1); err == nil {
+		return existing, nil
+	}
+	items, err := s.items.FindByTodo(todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	next := &models.Todo{
+		Title:        todo.Title,
+		Description:  todo.Description,
+		DueDate:      &due,
+		Priority:     todo.Priority,
+		OwnerID:      todo.OwnerID,
+		ProjectID:    todo.ProjectID,
+		AutoComplete: todo.AutoComplete,
+		RRule:        todo.RRule,
+		SeriesID:     &seriesID,
+		Occurrence:   todo.Occurrence + 1,
+	}
+	for _, it := range items {
+		next.Items = append(next.Items, models.TodoItem{Title: it.Title, Position: it.Position})
+	}
+	if err := s.repo.Create(next); err != nil {
+		return nil, err
+	}
+	if err := s.publish(events.TodoCreated{Todo: next}); err != nil {
+		return nil, err
+	}
+	next.Progress = progressOf(next.Items)
+	if err := s.copyOffsetReminders(todo.ID, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// copyOffsetReminders carries "N minutes before due" reminders over to the
+// next occurrence of a recurring todo.
+func (s *todoService) copyOffsetReminders(fromID uint, next *models.Todo) error {
+	rems, err := s.reminders.FindByTodo(fromID)
+	if err != nil {
+		return err
+	}
+	for _, r := range rems {
+		if r.OffsetMinutes == nil {
+			continue
+		}
+		at := next.DueDate.Add(-time.Duration(*r.OffsetMinutes) * time.Minute)
+		offset := *r.OffsetMinutes
+		rem := &models.Reminder{
+			TodoID:        next.ID,
+			UserID:        r.UserID,
+			OffsetMinutes: &offset,
+			FireAt:        &at,
+			Channel:       r.Channel,
+			Target:        r.Target,
+		}
+		if err := s.reminders.Create(rem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ownerLocation resolves the owner's timezone, falling back to UTC.
+func (s *todoService) ownerLocation(ownerID uint) *time.Location {
+	return userLocation(s.users, ownerID)
+}
+
+func userLocation(users repository.UserRepository, id uint) *time.Location {
+	u, err := users.FindByID(id)
+	if err != nil || u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// normalizeRRule validates the todo's recurrence rule and stores it in
+// canonical form.
+func normalizeRRule(todo *models.Todo) error {
+	if todo.RRule == "" {
+		return nil
+	}
+	rule, err := recurrence.Parse(todo.RRule)
+	if err != nil {
+		return err
+	}
+	if todo.DueDate == nil {
+		return errors.New("recurring todo requires due_date")
+	}
+	todo.RRule = rule.String()
+	return nil
 }
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/todoio"
+)
+
+// exportBatch is how many todos Export reads per query.
+const exportBatch = 200
+
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+	ImportFailed  ImportAction = "failed"
+)
+
+type ImportRowResult struct {
+	Row        int          `json:"row"`
+	ExternalID string       `json:"external_id,omitempty"`
+	Action     ImportAction `json:"action"`
+	ID         uint         `json:"id,omitempty"`
+	Errors     []string     `json:"errors,omitempty"`
+	Warnings   []string     `json:"warnings,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun  bool              `json:"dry_run"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// errDryRun rolls back the import transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// Export streams every todo matching filter to fn in batches, following
+// the filter's sort order.
+func (s *todoService) Export(filter repository.TodoFilter, fn func(todos []models.Todo) error) error {
+	page := repository.PageRequest{Limit: exportBatch}
+	for {
+		result, err := s.repo.FindAll(filter, page)
+		if err != nil {
+			return err
+		}
+		if len(result.Todos) > 0 {
+			if err := fn(result.Todos); err != nil {
+				return err
+			}
+		}
+		if result.Next == nil {
+			return nil
+		}
+		page.After = result.Next
+	}
+}
+
+// Import creates or updates the actor's todos from decoded records in one
+// transaction. A record whose external ID matches an existing todo of the
+// actor updates it instead of creating a duplicate. Each record runs in
+// its own savepoint so invalid rows are reported without affecting the
+// others. Projects named by records are looked up by name and created
+// when missing. A dry run does all the work and then rolls it back.
+func (s *todoService) Import(records []todoio.Record, dryRun bool, actor Actor) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun, Rows: make([]ImportRowResult, 0, len(records))}
+	err := s.tx.Do(func(r repository.Repos) error {
+		projects, err := s.importProjects(r, records, actor)
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			rec := &records[i]
+			row := ImportRowResult{Row: rec.Row, ExternalID: rec.ExternalID, Warnings: rec.Warnings}
+			err := rec.Err
+			if err == nil && rec.Project != "" {
+				id := projects[strings.ToLower(rec.Project)]
+				rec.Todo.ProjectID = &id
+			}
+			if err == nil {
+				err = r.Tx.Do(func(item repository.Repos) error {
+					var err error
+					row.Action, row.ID, err = s.withRepos(item).importOne(item, rec, actor)
+					return err
+				})
+			}
+			switch {
+			case err != nil:
+				row.Action, row.ID, row.Errors = ImportFailed, 0, validationMessages(err)
+				result.Failed++
+			case row.Action == ImportCreated:
+				result.Created++
+			default:
+				result.Updated++
+			}
+			if dryRun {
+				row.ID = 0
+			}
+			result.Rows = append(result.Rows, row)
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importProjects finds or creates the projects named by records and
+// returns their IDs by lowercased name.
+func (s *todoService) importProjects(r repository.Repos, records []todoio.Record, actor Actor) (map[string]uint, error) {
+	ids := map[string]uint{}
+	for i := range records {
+		name := records[i].Project
+		key := strings.ToLower(name)
+		if name == "" || records[i].Err != nil {
+			continue
+		}
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		p, err := r.Projects.FindByName(actor.ID, name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p = &models.Project{Name: name, OwnerID: actor.ID}
+			if err = s.validator.Struct(p); err != nil {
+				return nil, fmt.Errorf("project %q: %w", name, err)
+			}
+			err = r.Projects.Create(p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids[key] = p.ID
+	}
+	return ids, nil
+}
+
+func (s *todoService) importOne(r repository.Repos, rec *todoio.Record, actor Actor) (ImportAction, uint, error) {
+	input := rec.Todo
+	action := ImportCreated
+	var todo *models.Todo
+	var err error
+	if rec.ExternalID != "" {
+		existing, err := r.Todos.FindByExternalID(actor.ID, rec.ExternalID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, err
+		}
+		if err == nil {
+			if input.Priority == "" {
+				input.Priority = existing.Priority
+			}
+			action = ImportUpdated
+			todo, err = s.save(existing, &input, nil, actor)
+			if err != nil {
+				return "", 0, err
+			}
+			if err := s.importItems(r, todo.ID, input.Items); err != nil {
+				return "", 0, err
+			}
+		}
+	}
+	if todo == nil {
+		if rec.ExternalID != "" {
+			ext := rec.ExternalID
+			input.ExternalID = &ext
+		}
+		if todo, err = s.Create(&input, actor); err != nil {
+			return "", 0, err
+		}
+	}
+	if len(rec.Tags) > 0 {
+		names, err := normalizeTags(rec.Tags)
+		if err != nil {
+			return "", 0, err
+		}
+		tags, err := r.Tags.FindOrCreate(names)
+		if err != nil {
+			return "", 0, err
+		}
+		if err := r.Tags.Attach(todo.ID, tags); err != nil {
+			return "", 0, err
+		}
+	}
+	return action, todo.ID, nil
+}
+
+// importItems appends the checklist items of a re-imported todo whose
+// titles it does not have yet.
+func (s *todoService) importItems(r repository.Repos, todoID uint, items []models.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	existing, err := r.Items.FindByTodo(todoID)
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(existing))
+	for _, it := range existing {
+		have[it.Title] = true
+	}
+	pos, err := r.Items.NextPosition(todoID)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		if have[it.Title] {
+			continue
+		}
+		item := models.TodoItem{TodoID: todoID, Title: it.Title, Completed: it.Completed, Position: pos}
+		if err := s.validator.Struct(&item); err != nil {
+			return err
+		}
+		if err := r.Items.Create(&item); err != nil {
+			return err
+		}
+		have[it.Title] = true
+		pos++
+	}
+	return nil
+}
+
+// validationMessages flattens validator errors into one message per
+// field, using the JSON field names.
+func validationMessages(err error) []string {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return []string{err.Error()}
+	}
+	out := make([]string, len(verrs))
+	for i, fe := range verrs {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		out[i] = fmt.Sprintf("%s: failed %s", snakeCase(fe.Field()), rule)
+	}
+	return out
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
+)
+
+// signUser replaces the stored avatar URLs of u with URLs the client can
+// fetch. It must only be applied to users that are about to be returned,
+// never to ones that are saved afterwards.
+func signUser(urls *storage.URLSigner, u *models.User) {
+	if u == nil || !urls.Enabled() {
+		return
+	}
+	if key := storage.KeyOf(u.AvatarURL); key != "" {
+		u.AvatarURL = urls.URL(key, "")
+	}
+	if len(u.AvatarSizes) > 0 {
+		sizes := make(map[string]string, len(u.AvatarSizes))
+		for px, url := range u.AvatarSizes {
+			if key := storage.KeyOf(url); key != "" {
+				url = urls.URL(key, "")
+			}
+			sizes[px] = url
+		}
+		u.AvatarSizes = sizes
+	}
+}
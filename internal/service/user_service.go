@@ -1,34 +1,54 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/events"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/imaging"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
 	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/storage"
 )
 
 type UserService interface {
 	GetByID(id uint) (*models.User, error)
-	UpdateProfile(id uint, name string) (*models.User, error)
+	UpdateProfile(id uint, name, timezone string) (*models.User, error)
 	ChangePassword(id uint, oldPwd, newPwd string) error
-	UpdateAvatarURL(id uint, url string) (*models.User, error)
+	UpdateAvatar(id uint, data []byte) (*models.User, error)
 }
 
+// AvatarSizes are the square variants generated for every avatar, in
+// pixels. AvatarURL points at the largest one.
+var AvatarSizes = []int{64, 256}
+
 type userService struct {
-	repo repository.UserRepository
+	repo  repository.UserRepository
+	tx    repository.TxManager
+	blobs storage.BlobStore
+	urls  *storage.URLSigner
 }
 
-func NewUserService(r repository.UserRepository) UserService {
-	return &userService{repo: r}
+func NewUserService(r repository.UserRepository, tx repository.TxManager, blobs storage.BlobStore, urls *storage.URLSigner) UserService {
+	return &userService{repo: r, tx: tx, blobs: blobs, urls: urls}
 }
 
 func (s *userService) GetByID(id uint) (*models.User, error) {
-	return s.repo.FindByID(id)
+	u, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	signUser(s.urls, u)
+	return u, nil
 }
 
-func (s *userService) UpdateProfile(id uint, name string) (*models.User, error) {
+func (s *userService) UpdateProfile(id uint, name, timezone string) (*models.User, error) {
 	u, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
@@ -36,9 +56,22 @@ func (s *userService) UpdateProfile(id uint, name string) (*models.User, error)
 	if name != "" {
 		u.Name = name
 	}
-	if err := s.repo.Update(u); err != nil {
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, errors.New("invalid timezone")
+		}
+		u.Timezone = timezone
+	}
+	err = s.tx.Do(func(r repository.Repos) error {
+		if err := r.Users.Update(u); err != nil {
+			return err
+		}
+		return publish(r.Outbox, events.UserUpdated{User: u})
+	})
+	if err != nil {
 		return nil, err
 	}
+	signUser(s.urls, u)
 	return u, nil
 }
 
@@ -57,9 +90,67 @@ func (s *userService) ChangePassword(id uint, oldPwd, newPwd string) error {
 	return s.repo.SetPassword(id, string(hash))
 }
 
-func (s *userService) UpdateAvatarURL(id uint, url string) (*models.User, error) {
-	if err := s.repo.SetAvatar(id, url); err != nil {
+// UpdateAvatar decodes an uploaded image, stores freshly encoded square
+// variants (which carries no EXIF or other metadata over) and removes the
+// files of the previous avatar.
+func (s *userService) UpdateAvatar(id uint, data []byte) (*models.User, error) {
+	u, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	base, err := newStorageKey(fmt.Sprintf("avatars/%d", id), "")
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]string, len(AvatarSizes))
+	var written []string
+	var largest string
+	for _, px := range AvatarSizes {
+		var buf bytes.Buffer
+		ext, contentType, err := imaging.Encode(&buf, imaging.Square(img, px))
+		if err != nil {
+			removeBlobs(s.blobs, written...)
+			return nil, err
+		}
+		key := fmt.Sprintf("%s_%d%s", base, px, ext)
+		if err := s.blobs.Put(context.Background(), key, &buf, int64(buf.Len()), contentType); err != nil {
+			removeBlobs(s.blobs, written...)
+			return nil, err
+		}
+		written = append(written, key)
+		largest = storage.PublicPrefix + key
+		sizes[strconv.Itoa(px)] = largest
+	}
+	if err := s.repo.SetAvatar(id, largest, sizes); err != nil {
+		removeBlobs(s.blobs, written...)
 		return nil, err
 	}
-	return s.repo.FindByID(id)
+	removeBlobs(s.blobs, avatarKeys(u)...)
+	u.AvatarURL = largest
+	u.AvatarSizes = sizes
+	signUser(s.urls, u)
+	return u, nil
+}
+
+// avatarKeys lists the stored files behind a user's current avatar,
+// including uploads from before variants existed.
+func avatarKeys(u *models.User) []string {
+	seen := map[string]bool{}
+	var keys []string
+	add := func(url string) {
+		key := storage.KeyOf(url)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	add(u.AvatarURL)
+	for _, url := range u.AvatarSizes {
+		add(url)
+	}
+	return keys
 }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/filter"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// SystemViews are the built-in views offered to every user. Their filters
+// use today/now, which are resolved in the user's timezone.
+var SystemViews = []models.SavedView{
+	{Key: "today", Name: "Today", Filter: "not completed and due_date >= today and due_date < today+1d", Sort: "due_asc", IncludeShared: true, System: true},
+	{Key: "upcoming", Name: "Upcoming", Filter: "not completed and due_date >= today+1d and due_date < today+8d", Sort: "due_asc", IncludeShared: true, System: true},
+	{Key: "overdue", Name: "Overdue", Filter: "not completed and due_date < today", Sort: "due_asc", IncludeShared: true, System: true},
+}
+
+type ViewService interface {
+	List(actor Actor) ([]models.SavedView, error)
+	Get(ref string, actor Actor) (*models.SavedView, error)
+	Create(input *models.SavedView, actor Actor) (*models.SavedView, error)
+	Update(id uint, input *models.SavedView, actor Actor) (*models.SavedView, error)
+	Delete(id uint, actor Actor) error
+	Todos(ref string, page repository.PageRequest, actor Actor) (*repository.TodoPage, error)
+}
+
+type viewService struct {
+	repo      repository.ViewRepository
+	users     repository.UserRepository
+	todos     TodoService
+	validator *validator.Validate
+}
+
+func NewViewService(r repository.ViewRepository, users repository.UserRepository, todos TodoService) ViewService {
+	return &viewService{repo: r, users: users, todos: todos, validator: validator.New()}
+}
+
+// List returns the system views followed by the user's own views.
+func (s *viewService) List(actor Actor) ([]models.SavedView, error) {
+	own, err := s.repo.FindByOwner(actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]models.SavedView{}, SystemViews...), own...), nil
+}
+
+// Get resolves ref, which is either a system view key or the ID of one of
+// the actor's views.
+func (s *viewService) Get(ref string, actor Actor) (*models.SavedView, error) {
+	for _, v := range SystemViews {
+		if v.Key == ref {
+			return &v, nil
+		}
+	}
+	id, err := strconv.ParseUint(ref, 10, 64)
+	if err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.owned(uint(id), actor)
+}
+
+func (s *viewService) owned(id uint, actor Actor) (*models.SavedView, error) {
+	v, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if v.OwnerID != actor.ID {
+		return nil, ErrForbidden
+	}
+	return v, nil
+}
+
+func (s *viewService) Create(input *models.SavedView, actor Actor) (*models.SavedView, error) {
+	v := &models.SavedView{OwnerID: actor.ID}
+	assignView(v, input)
+	if err := s.validate(v); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Update replaces the view's parameters with input.
+func (s *viewService) Update(id uint, input *models.SavedView, actor Actor) (*models.SavedView, error) {
+	v, err := s.owned(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	assignView(v, input)
+	if err := s.validate(v); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *viewService) Delete(id uint, actor Actor) error {
+	if _, err := s.owned(id, actor); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// Todos lists the todos matching a view, exactly as GET /todos would with
+// the view's parameters.
+func (s *viewService) Todos(ref string, page repository.PageRequest, actor Actor) (*repository.TodoPage, error) {
+	v, err := s.Get(ref, actor)
+	if err != nil {
+		return nil, err
+	}
+	where, err := filter.ParseAt(v.Filter, repository.TodoFilterFields, s.todos.Now(actor))
+	if err != nil {
+		return nil, err
+	}
+	sort := v.Sort
+	if sort == "" {
+		sort = "created_asc"
+	}
+	return s.todos.List(repository.TodoFilter{
+		Search:        v.Query,
+		ProjectID:     v.ProjectID,
+		Sort:          sort,
+		OwnerID:       &actor.ID,
+		IncludeShared: v.IncludeShared,
+		Where:         where,
+	}, page)
+}
+
+func (s *viewService) validate(v *models.SavedView) error {
+	if err := s.validator.Struct(v); err != nil {
+		return err
+	}
+	_, err := filter.Parse(v.Filter, repository.TodoFilterFields)
+	return err
+}
+
+func assignView(v, input *models.SavedView) {
+	v.Name = input.Name
+	v.Query = input.Query
+	v.Filter = input.Filter
+	v.Sort = input.Sort
+	v.ProjectID = input.ProjectID
+	v.IncludeShared = input.IncludeShared
+}
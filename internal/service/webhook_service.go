@@ -0,0 +1,195 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/repository"
+)
+
+// MaxDeliveryPage caps how many deliveries one listing returns.
+const MaxDeliveryPage = 100
+
+// WebhookService manages webhook endpoints and their delivery logs.
+// Deliveries themselves are made by scheduler.WebhookDispatcher.
+type WebhookService interface {
+	List(actor Actor) ([]models.Webhook, error)
+	Get(id uint, actor Actor) (*models.Webhook, error)
+	Create(input *models.Webhook, actor Actor) (*models.Webhook, error)
+	Update(id uint, input *models.Webhook, actor Actor) (*models.Webhook, error)
+	Delete(id uint, actor Actor) error
+	RotateSecret(id uint, actor Actor) (*models.Webhook, error)
+	Deliveries(id uint, status models.DeliveryStatus, beforeID uint, limit int, actor Actor) ([]models.WebhookDelivery, error)
+	Delivery(id, deliveryID uint, actor Actor) (*models.WebhookDelivery, error)
+	Redeliver(id, deliveryID uint, actor Actor) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo      repository.WebhookRepository
+	validator *validator.Validate
+}
+
+func NewWebhookService(r repository.WebhookRepository) WebhookService {
+	return &webhookService{repo: r, validator: validator.New()}
+}
+
+func (s *webhookService) List(actor Actor) ([]models.Webhook, error) {
+	return s.repo.FindByOwner(actor.ID)
+}
+
+// Get returns a webhook of the actor; admins may read any.
+func (s *webhookService) Get(id uint, actor Actor) (*models.Webhook, error) {
+	w, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if w.OwnerID != actor.ID && !actor.IsAdmin() {
+		return nil, ErrForbidden
+	}
+	return w, nil
+}
+
+// Create registers a webhook with a new signing secret, which is only
+// returned here and by RotateSecret.
+func (s *webhookService) Create(input *models.Webhook, actor Actor) (*models.Webhook, error) {
+	w := &models.Webhook{OwnerID: actor.ID, Active: true}
+	if err := s.apply(w, input, actor); err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	w.Secret = secret
+	if err := s.repo.Create(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *webhookService) Update(id uint, input *models.Webhook, actor Actor) (*models.Webhook, error) {
+	w, err := s.Get(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(w, input, actor); err != nil {
+		return nil, err
+	}
+	w.Active = input.Active
+	if err := s.repo.Update(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *webhookService) Delete(id uint, actor Actor) error {
+	if _, err := s.Get(id, actor); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *webhookService) RotateSecret(id uint, actor Actor) (*models.Webhook, error) {
+	w, err := s.Get(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if w.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// apply validates and copies the editable fields of input onto w.
+func (s *webhookService) apply(w, input *models.Webhook, actor Actor) error {
+	if err := s.validator.Struct(input); err != nil {
+		return err
+	}
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	events, err := normalizeEvents(input.Events)
+	if err != nil {
+		return err
+	}
+	if input.Global && !actor.IsAdmin() {
+		return ErrForbidden
+	}
+	w.URL = input.URL
+	w.Description = input.Description
+	w.Events = events
+	w.Global = input.Global
+	return nil
+}
+
+func normalizeEvents(events []string) ([]string, error) {
+	known := make(map[string]bool, len(models.EventTypes))
+	for _, e := range models.EventTypes {
+		known[e] = true
+	}
+	out := make([]string, 0, len(events))
+	seen := map[string]bool{}
+	for _, e := range events {
+		if !known[e] {
+			return nil, fmt.Errorf("unknown event %q", e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func (s *webhookService) Deliveries(id uint, status models.DeliveryStatus, beforeID uint, limit int, actor Actor) ([]models.WebhookDelivery, error) {
+	if _, err := s.Get(id, actor); err != nil {
+		return nil, err
+	}
+	switch status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryDead:
+	default:
+		return nil, fmt.Errorf("unknown status %q", status)
+	}
+	if limit <= 0 || limit > MaxDeliveryPage {
+		limit = MaxDeliveryPage
+	}
+	return s.repo.FindDeliveries(id, status, beforeID, limit)
+}
+
+func (s *webhookService) Delivery(id, deliveryID uint, actor Actor) (*models.WebhookDelivery, error) {
+	if _, err := s.Get(id, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.FindDelivery(id, deliveryID)
+}
+
+// Redeliver queues a delivery to be sent again, also one that is dead or
+// already succeeded.
+func (s *webhookService) Redeliver(id, deliveryID uint, actor Actor) (*models.WebhookDelivery, error) {
+	if _, err := s.Get(id, actor); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Redeliver(id, deliveryID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.FindDelivery(id, deliveryID)
+}
@@ -0,0 +1,51 @@
+// Package storage abstracts where uploaded files live. Objects are
+// addressed by slash-separated keys such as "attachments/12/ab34.pdf".
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/config"
+)
+
+var ErrNotFound = errors.New("storage: object not found")
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// GetRange reads length bytes starting at offset. The returned info
+	// describes the whole object.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, *ObjectInfo, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the store selected by STORAGE_DRIVER.
+func New(cfg *config.Config) (BlobStore, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStore(cfg.UploadDir), nil
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	}
+	return nil, fmt.Errorf("storage: unknown driver %q", cfg.StorageDriver)
+}
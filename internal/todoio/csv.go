@@ -0,0 +1,177 @@
+package todoio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// csvColumns is the header written by exports. Imports match columns by
+// header name in any order and ignore unknown ones; only title is
+// required.
+var csvColumns = []string{
+	"external_id", "title", "description", "completed", "due_date", "priority",
+	"project_id", "tags", "rrule", "auto_complete", "created_at", "updated_at",
+}
+
+// csvTagSeparator joins tags in the tags column.
+const csvTagSeparator = ";"
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+	e := &csvEncoder{w: csv.NewWriter(w)}
+	if err := e.w.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(t *models.Todo) error {
+	due, project := "", ""
+	if t.DueDate != nil {
+		due = t.DueDate.UTC().Format(time.RFC3339)
+	}
+	if t.ProjectID != nil {
+		project = strconv.FormatUint(uint64(*t.ProjectID), 10)
+	}
+	return e.w.Write([]string{
+		UID(t),
+		t.Title,
+		t.Description,
+		strconv.FormatBool(t.Completed),
+		due,
+		string(t.Priority),
+		project,
+		strings.Join(tagNames(t.Tags), csvTagSeparator),
+		t.RRule,
+		strconv.FormatBool(t.AutoComplete),
+		t.CreatedAt.UTC().Format(time.RFC3339),
+		t.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func decodeCSV(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV")
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	if _, ok := col["title"]; !ok {
+		return nil, errors.New("CSV header has no title column")
+	}
+
+	var out []Record
+	for row := 1; ; row++ {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if len(out) == MaxRecords {
+			return nil, tooMany()
+		}
+		rec := Record{Row: row}
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return nil, err
+			}
+			rec.Err = err
+			out = append(out, rec)
+			continue
+		}
+		raw := func(name string) string {
+			if i, ok := col[name]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		get := func(name string) string {
+			return strings.TrimSpace(raw(name))
+		}
+		rec.ExternalID = get("external_id")
+		rec.Todo = models.Todo{
+			Title: get("title"),
+			// The description is kept verbatim, as the JSON and
+			// iCalendar imports do, so exports round-trip.
+			Description: raw("description"),
+			Priority:    models.Priority(strings.ToLower(get("priority"))),
+			RRule:       get("rrule"),
+		}
+		rec.Err = parseCSVFields(&rec, get)
+		out = append(out, rec)
+	}
+}
+
+func parseCSVFields(rec *Record, get func(string) string) error {
+	var err error
+	if rec.Todo.Completed, err = parseBool(get("completed")); err != nil {
+		return fmt.Errorf("completed: %w", err)
+	}
+	if rec.Todo.AutoComplete, err = parseBool(get("auto_complete")); err != nil {
+		return fmt.Errorf("auto_complete: %w", err)
+	}
+	if v := get("due_date"); v != "" {
+		due, err := parseDate(v)
+		if err != nil {
+			return fmt.Errorf("due_date: %w", err)
+		}
+		rec.Todo.DueDate = &due
+	}
+	if v := get("project_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("project_id: invalid number %q", v)
+		}
+		pid := uint(id)
+		rec.Todo.ProjectID = &pid
+	}
+	for _, tag := range strings.Split(get("tags"), csvTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			rec.Tags = append(rec.Tags, tag)
+		}
+	}
+	return nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "", "false", "0", "no":
+		return false, nil
+	case "true", "1", "yes", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// parseDate accepts RFC 3339 timestamps and plain dates (midnight UTC).
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use RFC 3339 or YYYY-MM-DD)", s)
+}
@@ -0,0 +1,191 @@
+package todoio
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/ical"
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// ProdID identifies this application in generated calendars.
+const ProdID = "-//go-fiber-gorm-todo//Todos//EN"
+
+// Priorities in iCalendar run from 1 (highest) to 9 (lowest); 0 means
+// undefined.
+var icalPriority = map[models.Priority]string{
+	models.PriorityHigh:   "1",
+	models.PriorityMedium: "5",
+	models.PriorityLow:    "9",
+}
+
+func priorityOf(v string) models.Priority {
+	switch strings.TrimSpace(v) {
+	case "1", "2", "3", "4":
+		return models.PriorityHigh
+	case "6", "7", "8", "9":
+		return models.PriorityLow
+	}
+	return models.PriorityMedium
+}
+
+// NewCalendar returns an empty VCALENDAR with the required properties.
+func NewCalendar(name string) *ical.Component {
+	cal := ical.NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", ProdID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	if name != "" {
+		cal.AddText("X-WR-CALNAME", name)
+	}
+	return cal
+}
+
+// VTodo renders a todo as a VTODO with the given UID.
+func VTodo(t *models.Todo, uid string) *ical.Component {
+	c := ical.NewComponent("VTODO")
+	c.AddText("UID", uid)
+	c.Add("DTSTAMP", ical.FormatTime(t.UpdatedAt))
+	c.Add("CREATED", ical.FormatTime(t.CreatedAt))
+	c.Add("LAST-MODIFIED", ical.FormatTime(t.UpdatedAt))
+	c.Add("SEQUENCE", strconv.FormatUint(uint64(t.Version), 10))
+	c.AddText("SUMMARY", t.Title)
+	if t.Description != "" {
+		c.AddText("DESCRIPTION", t.Description)
+	}
+	if t.DueDate != nil {
+		c.Add("DUE", ical.FormatTime(*t.DueDate))
+	}
+	if p, ok := icalPriority[t.Priority]; ok {
+		c.Add("PRIORITY", p)
+	}
+	if t.Completed {
+		c.Add("STATUS", "COMPLETED")
+		c.Add("COMPLETED", ical.FormatTime(t.UpdatedAt))
+		c.Add("PERCENT-COMPLETE", "100")
+	} else {
+		c.Add("STATUS", "NEEDS-ACTION")
+	}
+	if t.RRule != "" {
+		c.Add("RRULE", t.RRule)
+	}
+	if len(t.Tags) > 0 {
+		names := tagNames(t.Tags)
+		for i := range names {
+			names[i] = ical.EscapeText(names[i])
+		}
+		c.Add("CATEGORIES", strings.Join(names, ","))
+	}
+	return c
+}
+
+// VEvent renders a todo with a due date as a zero-length VEVENT at the
+// due time, for calendar apps that do not show tasks.
+func VEvent(t *models.Todo, uid string) *ical.Component {
+	c := ical.NewComponent("VEVENT")
+	c.AddText("UID", uid)
+	c.Add("DTSTAMP", ical.FormatTime(t.UpdatedAt))
+	c.Add("LAST-MODIFIED", ical.FormatTime(t.UpdatedAt))
+	c.Add("SEQUENCE", strconv.FormatUint(uint64(t.Version), 10))
+	c.AddText("SUMMARY", t.Title)
+	if t.Description != "" {
+		c.AddText("DESCRIPTION", t.Description)
+	}
+	c.Add("DTSTART", ical.FormatTime(*t.DueDate))
+	c.Add("DTEND", ical.FormatTime(*t.DueDate))
+	c.Add("TRANSP", "TRANSPARENT")
+	if t.Completed {
+		c.Add("STATUS", "CANCELLED")
+	}
+	return c
+}
+
+// FromVTodo converts a VTODO (or a VEVENT, whose start becomes the due
+// date) into a todo and its tags. Floating times are taken in loc.
+func FromVTodo(c *ical.Component, loc *time.Location) (models.Todo, []string, error) {
+	t := models.Todo{
+		Title:       strings.TrimSpace(c.Text("SUMMARY")),
+		Description: c.Text("DESCRIPTION"),
+		Priority:    models.PriorityMedium,
+	}
+	if p := c.Get("PRIORITY"); p != nil {
+		t.Priority = priorityOf(p.Value)
+	}
+	status := strings.ToUpper(c.Text("STATUS"))
+	t.Completed = status == "COMPLETED" || c.Get("COMPLETED") != nil
+	due := c.Get("DUE")
+	if due == nil {
+		due = c.Get("DTSTART")
+	}
+	if due != nil {
+		d, err := ical.ParseTime(due, loc)
+		if err != nil {
+			return t, nil, errors.New("invalid " + due.Name)
+		}
+		t.DueDate = &d
+	}
+	if p := c.Get("RRULE"); p != nil {
+		t.RRule = p.Value
+	}
+	var tags []string
+	for _, p := range c.GetAll("CATEGORIES") {
+		for _, name := range ical.SplitText(p.Value) {
+			if name = strings.TrimSpace(name); name != "" {
+				tags = append(tags, name)
+			}
+		}
+	}
+	return t, tags, nil
+}
+
+type icsEncoder struct {
+	w *ical.Writer
+}
+
+func newICSEncoder(w io.Writer) *icsEncoder {
+	e := &icsEncoder{w: ical.NewWriter(w)}
+	e.w.Begin("VCALENDAR")
+	for _, p := range NewCalendar("Todos").Props {
+		e.w.Prop(p)
+	}
+	return e
+}
+
+func (e *icsEncoder) Encode(t *models.Todo) error {
+	e.w.Component(VTodo(t, UID(t)))
+	return nil
+}
+
+func (e *icsEncoder) Close() error {
+	e.w.End("VCALENDAR")
+	return e.w.Flush()
+}
+
+func decodeICS(r io.Reader) ([]Record, error) {
+	cal, err := ical.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if cal.Name != "VCALENDAR" {
+		return nil, errors.New("expected a VCALENDAR")
+	}
+	var out []Record
+	for _, c := range cal.Components {
+		if c.Name != "VTODO" && c.Name != "VEVENT" {
+			continue
+		}
+		if len(out) == MaxRecords {
+			return nil, tooMany()
+		}
+		rec := Record{Row: len(out) + 1, ExternalID: strings.TrimSpace(c.Text("UID"))}
+		rec.Todo, rec.Tags, rec.Err = FromVTodo(c, time.UTC)
+		out = append(out, rec)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no VTODO or VEVENT to import")
+	}
+	return out, nil
+}
@@ -0,0 +1,110 @@
+package todoio
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+// jsonTodo is the exchange representation of a todo.
+type jsonTodo struct {
+	ExternalID   string          `json:"external_id"`
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	Completed    bool            `json:"completed"`
+	DueDate      *time.Time      `json:"due_date"`
+	Priority     models.Priority `json:"priority"`
+	ProjectID    *uint           `json:"project_id"`
+	Tags         []string        `json:"tags"`
+	RRule        string          `json:"rrule"`
+	AutoComplete bool            `json:"auto_complete"`
+	CreatedAt    *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
+}
+
+type jsonEncoder struct {
+	w     *bufio.Writer
+	first bool
+}
+
+func newJSONEncoder(w io.Writer) (*jsonEncoder, error) {
+	e := &jsonEncoder{w: bufio.NewWriter(w), first: true}
+	_, err := e.w.WriteString("[")
+	return e, err
+}
+
+func (e *jsonEncoder) Encode(t *models.Todo) error {
+	created, updated := t.CreatedAt, t.UpdatedAt
+	b, err := json.Marshal(jsonTodo{
+		ExternalID:   UID(t),
+		Title:        t.Title,
+		Description:  t.Description,
+		Completed:    t.Completed,
+		DueDate:      t.DueDate,
+		Priority:     t.Priority,
+		ProjectID:    t.ProjectID,
+		Tags:         tagNames(t.Tags),
+		RRule:        t.RRule,
+		AutoComplete: t.AutoComplete,
+		CreatedAt:    &created,
+		UpdatedAt:    &updated,
+	})
+	if err != nil {
+		return err
+	}
+	if !e.first {
+		if err := e.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	e.first = false
+	_, err = e.w.Write(append([]byte("\n"), b...))
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	if _, err := e.w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+func decodeJSON(r io.Reader) ([]Record, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of todos: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("no todos to import")
+	}
+	if len(raw) > MaxRecords {
+		return nil, tooMany()
+	}
+	out := make([]Record, len(raw))
+	for i, msg := range raw {
+		var jt jsonTodo
+		out[i].Row = i + 1
+		if err := json.Unmarshal(msg, &jt); err != nil {
+			out[i].Err = err
+			continue
+		}
+		out[i].ExternalID = jt.ExternalID
+		out[i].Tags = jt.Tags
+		out[i].Todo = models.Todo{
+			Title:        jt.Title,
+			Description:  jt.Description,
+			Completed:    jt.Completed,
+			DueDate:      jt.DueDate,
+			Priority:     jt.Priority,
+			ProjectID:    jt.ProjectID,
+			RRule:        jt.RRule,
+			AutoComplete: jt.AutoComplete,
+		}
+	}
+	return out, nil
+}
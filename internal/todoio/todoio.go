@@ -0,0 +1,128 @@
+// Package todoio converts todos to and from the CSV, JSON and iCalendar
+// formats used by import and export.
+package todoio
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+	ICS  Format = "ics"
+)
+
+// MaxRecords caps the number of todos read from one import.
+const MaxRecords = 1000
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSON, ICS:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q (use csv, json or ics)", s)
+}
+
+// FormatOfContentType guesses the format of an upload from its media
+// type.
+func FormatOfContentType(ct string) (Format, bool) {
+	ct = strings.ToLower(strings.TrimSpace(strings.Split(ct, ";")[0]))
+	switch ct {
+	case "text/csv", "application/csv":
+		return CSV, true
+	case "application/json":
+		return JSON, true
+	case "text/calendar":
+		return ICS, true
+	}
+	return "", false
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case ICS:
+		return "text/calendar; charset=utf-8"
+	}
+	return "application/json"
+}
+
+// UID is the stable external identifier of a todo: its ExternalID when it
+// was imported, todo-<id> otherwise. Importing an export therefore
+// matches the original todos instead of duplicating them.
+func UID(t *models.Todo) string {
+	if t.ExternalID != nil && *t.ExternalID != "" {
+		return *t.ExternalID
+	}
+	return "todo-" + strconv.FormatUint(uint64(t.ID), 10)
+}
+
+// Record is one todo read from an import. Row is 1-based (data rows for
+// CSV, array elements for JSON, VTODOs for iCalendar). Err is set when the
+// record could not be converted; Todo is then incomplete.
+type Record struct {
+	Row        int
+	ExternalID string
+	Todo       models.Todo
+	Tags       []string
+	// Project names a project of the importing user to put the todo in;
+	// it is created when missing.
+	Project string
+	// Warnings note source data that was dropped while decoding.
+	Warnings []string
+	Err      error
+}
+
+// Encoder writes todos in one format. Close writes the trailer; nothing
+// may be written after it.
+type Encoder interface {
+	Encode(t *models.Todo) error
+	Close() error
+}
+
+func NewEncoder(f Format, w io.Writer) (Encoder, error) {
+	switch f {
+	case CSV:
+		return newCSVEncoder(w)
+	case JSON:
+		return newJSONEncoder(w)
+	case ICS:
+		return newICSEncoder(w), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", f)
+}
+
+// Decode reads every record of an import. It fails only when the input
+// as a whole is unreadable; problems with single records are reported in
+// Record.Err.
+func Decode(f Format, r io.Reader) ([]Record, error) {
+	switch f {
+	case CSV:
+		return decodeCSV(r)
+	case JSON:
+		return decodeJSON(r)
+	case ICS:
+		return decodeICS(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", f)
+}
+
+func tooMany() error {
+	return fmt.Errorf("at most %d todos per import", MaxRecords)
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
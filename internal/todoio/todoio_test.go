@@ -0,0 +1,289 @@
+package todoio
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourname/go-fiber-gorm-todo-auth-swagger/internal/models"
+)
+
+func sampleTodos() []models.Todo {
+	due := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	project := uint(3)
+	external := "todoist:123"
+	created := time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC)
+	return []models.Todo{
+		{
+			ID:           7,
+			Title:        `Buy milk, eggs; "bread"`,
+			Description:  "line one\nline two, with a \\ backslash; and a semicolon",
+			Completed:    true,
+			DueDate:      &due,
+			Priority:     models.PriorityHigh,
+			ProjectID:    &project,
+			Tags:         []models.Tag{{Name: "work"}, {Name: "home office"}},
+			RRule:        "FREQ=WEEKLY;BYDAY=MO,WE",
+			AutoComplete: true,
+			Version:      4,
+			CreatedAt:    created,
+			UpdatedAt:    created.Add(time.Hour),
+		},
+		{
+			ID:          8,
+			ExternalID:  &external,
+			Title:       "Ünïcödé 日本語のタスク",
+			Description: strings.Repeat("Déjà vu — 長い説明, ", 20),
+			Priority:    models.PriorityLow,
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		},
+		{
+			ID:        9,
+			Title:     "Minimal",
+			Priority:  models.PriorityMedium,
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+	}
+}
+
+func encodeAll(t *testing.T, f Format, todos []models.Todo) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc, err := NewEncoder(f, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range todos {
+		if err := enc.Encode(&todos[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range []Format{CSV, JSON, ICS} {
+		t.Run(string(f), func(t *testing.T) {
+			todos := sampleTodos()
+			data := encodeAll(t, f, todos)
+			recs, err := Decode(f, bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Decode: %v\n%s", err, data)
+			}
+			if len(recs) != len(todos) {
+				t.Fatalf("got %d records, want %d", len(recs), len(todos))
+			}
+			for i, rec := range recs {
+				want := &todos[i]
+				got := rec.Todo
+				if rec.Err != nil {
+					t.Errorf("record %d: %v", rec.Row, rec.Err)
+					continue
+				}
+				if rec.Row != i+1 {
+					t.Errorf("record %d: Row = %d", i+1, rec.Row)
+				}
+				if rec.ExternalID != UID(want) {
+					t.Errorf("record %d: ExternalID = %q, want %q", rec.Row, rec.ExternalID, UID(want))
+				}
+				if got.Title != want.Title || got.Description != want.Description {
+					t.Errorf("record %d: text = %q / %q, want %q / %q", rec.Row, got.Title, got.Description, want.Title, want.Description)
+				}
+				if got.Completed != want.Completed || got.Priority != want.Priority || got.RRule != want.RRule {
+					t.Errorf("record %d: got %v %q %q, want %v %q %q", rec.Row,
+						got.Completed, got.Priority, got.RRule, want.Completed, want.Priority, want.RRule)
+				}
+				if (got.DueDate == nil) != (want.DueDate == nil) || got.DueDate != nil && !got.DueDate.Equal(*want.DueDate) {
+					t.Errorf("record %d: DueDate = %v, want %v", rec.Row, got.DueDate, want.DueDate)
+				}
+				if wantTags := tagNames(want.Tags); len(wantTags) > 0 || len(rec.Tags) > 0 {
+					if !reflect.DeepEqual(rec.Tags, wantTags) {
+						t.Errorf("record %d: Tags = %q, want %q", rec.Row, rec.Tags, wantTags)
+					}
+				}
+				// iCalendar has no place for the project or auto-complete.
+				if f == ICS {
+					continue
+				}
+				if !reflect.DeepEqual(got.ProjectID, want.ProjectID) || got.AutoComplete != want.AutoComplete {
+					t.Errorf("record %d: ProjectID, AutoComplete = %v, %v; want %v, %v", rec.Row,
+						got.ProjectID, got.AutoComplete, want.ProjectID, want.AutoComplete)
+				}
+			}
+		})
+	}
+}
+
+func TestICSOutput(t *testing.T) {
+	data := string(encodeAll(t, ICS, sampleTodos()[:2]))
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:" + ProdID + "\r\n",
+		"UID:todo-7\r\n",
+		`SUMMARY:Buy milk\, eggs\; "bread"` + "\r\n",
+		`DESCRIPTION:line one\nline two\, with a \\ backslash\; and a semicolon` + "\r\n",
+		"DUE:20240501T093000Z\r\n",
+		"PRIORITY:1\r\n",
+		"STATUS:COMPLETED\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE\r\n",
+		"CATEGORIES:work,home office\r\n",
+		"SEQUENCE:4\r\n",
+		"UID:todoist:123\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("output lacks %q", want)
+		}
+	}
+	for i, line := range strings.Split(data, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i+1, len(line))
+		}
+	}
+	if !strings.Contains(data, "\r\n ") {
+		t.Error("long description was not folded")
+	}
+}
+
+func TestDecodeICS(t *testing.T) {
+	src := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//Other App//EN",
+		"BEGIN:VTIMEZONE",
+		"TZID:America/New_York",
+		"END:VTIMEZONE",
+		"BEGIN:VTODO",
+		"UID:abc@example.com",
+		"SUMMARY:Call the plumber\\, again",
+		"DESCRIPTION:Ask about the\\nkitchen sink",
+		"DUE;TZID=America/New_York:20240501T090000",
+		"PRIORITY:2",
+		"CATEGORIES:home,errands",
+		"CATEGORIES:urgent\\,ish",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:def@example.com",
+		"SUMMARY:Renew passport",
+		"DUE;VALUE=DATE:20240601",
+		"COMPLETED:20240520T100000Z",
+		"PRIORITY:0",
+		"END:VTODO",
+		"BEGIN:VEVENT",
+		"UID:ghi@example.com",
+		"SUMMARY:Dentist",
+		"DTSTART:20240610T140000Z",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:bad@example.com",
+		"SUMMARY:Broken",
+		"DUE:not-a-date",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+	recs, err := Decode(ICS, strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 4 {
+		t.Fatalf("got %d records, want 4", len(recs))
+	}
+
+	first := recs[0]
+	if first.ExternalID != "abc@example.com" || first.Todo.Title != "Call the plumber, again" ||
+		first.Todo.Description != "Ask about the\nkitchen sink" || first.Todo.Priority != models.PriorityHigh {
+		t.Errorf("first record = %+v", first)
+	}
+	if want := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC); first.Todo.DueDate == nil || !first.Todo.DueDate.Equal(want) {
+		t.Errorf("first DueDate = %v, want %v", first.Todo.DueDate, want)
+	}
+	if want := []string{"home", "errands", "urgent,ish"}; !reflect.DeepEqual(first.Tags, want) {
+		t.Errorf("first Tags = %q, want %q", first.Tags, want)
+	}
+
+	second := recs[1]
+	if !second.Todo.Completed || second.Todo.Priority != models.PriorityMedium {
+		t.Errorf("second record = %+v", second.Todo)
+	}
+	if want := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC); second.Todo.DueDate == nil || !second.Todo.DueDate.Equal(want) {
+		t.Errorf("second DueDate = %v, want %v", second.Todo.DueDate, want)
+	}
+
+	if event := recs[2]; event.Todo.Title != "Dentist" || event.Todo.DueDate == nil || event.Todo.DueDate.Hour() != 14 {
+		t.Errorf("event record = %+v", event.Todo)
+	}
+	if recs[3].Err == nil {
+		t.Error("invalid DUE was accepted")
+	}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	src := "\ufeffTitle,Priority,unknown,Due_Date,Completed,Tags,Description\n" +
+		"Plain,HIGH,x,2024-05-01,yes,a; b ;,\n" +
+		"\"Quoted, with comma\",low,,2024-05-01T09:00:00+07:00,0,,\"multi\nline \"\"quoted\"\"\"\n" +
+		"Bad bool,medium,,,maybe,,\n" +
+		"Bad date,medium,,31/12/2024,,,\n" +
+		"Short row\n"
+	recs, err := Decode(CSV, strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 5 {
+		t.Fatalf("got %d records, want 5", len(recs))
+	}
+
+	if r := recs[0]; r.Err != nil || r.Todo.Title != "Plain" || r.Todo.Priority != models.PriorityHigh ||
+		!r.Todo.Completed || !reflect.DeepEqual(r.Tags, []string{"a", "b"}) ||
+		r.Todo.DueDate == nil || !r.Todo.DueDate.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first record = %+v", r)
+	}
+	if r := recs[1]; r.Err != nil || r.Todo.Title != "Quoted, with comma" ||
+		r.Todo.Description != "multi\nline \"quoted\"" || r.Todo.Completed ||
+		r.Todo.DueDate == nil || !r.Todo.DueDate.Equal(time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("second record = %+v", r)
+	}
+	if recs[2].Err == nil || !strings.Contains(recs[2].Err.Error(), "completed") {
+		t.Errorf("bad boolean: err = %v", recs[2].Err)
+	}
+	if recs[3].Err == nil || !strings.Contains(recs[3].Err.Error(), "due_date") {
+		t.Errorf("bad date: err = %v", recs[3].Err)
+	}
+	if r := recs[4]; r.Err != nil || r.Todo.Title != "Short row" || r.Row != 5 {
+		t.Errorf("short row = %+v", r)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		f   Format
+		src string
+	}{
+		{CSV, ""},
+		{CSV, "name,done\nx,1\n"},
+		{JSON, `{"title":"not an array"}`},
+		{JSON, `[]`},
+		{ICS, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"},
+		{ICS, "BEGIN:VTODO\r\nSUMMARY:x\r\nEND:VTODO\r\n"},
+		{ICS, "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\n"},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(tt.f, strings.NewReader(tt.src)); err == nil {
+			t.Errorf("Decode(%s, %q) succeeded, want error", tt.f, tt.src)
+		}
+	}
+
+	var many strings.Builder
+	many.WriteString("title\n")
+	for i := 0; i <= MaxRecords; i++ {
+		many.WriteString("t\n")
+	}
+	if _, err := Decode(CSV, strings.NewReader(many.String())); err == nil {
+		t.Errorf("Decode accepted %d records", MaxRecords+1)
+	}
+}
@@ -6,6 +6,8 @@ type Meta struct {
 	Limit int `json:"limit,omitempty"`
 	Page  int `json:"page,omitempty"`
 	Total int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 func OK(c *fiber.Ctx, data interface{}) error {
@@ -24,6 +26,10 @@ func Created(c *fiber.Ctx, data interface{}) error {
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"status": true, "data": data})
 }
 
+func Accepted(c *fiber.Ctx, data interface{}) error {
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"status": true, "data": data})
+}
+
 func NoContent(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }